@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Attributes describe a Kubernetes API request to authorize. An empty
+// Namespace asks about every namespace.
+type Attributes struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+	Namespace   string
+	Name        string
+}
+
+func (a Attributes) String() string {
+	resource := a.Resource
+	if a.Subresource != "" {
+		resource += "/" + a.Subresource
+	}
+
+	scope := "all namespaces"
+	if a.Namespace != "" {
+		scope = "namespace " + a.Namespace
+	}
+
+	return a.Verb + " " + resource + " in " + scope
+}
+
+// Authorizer decides whether a user may perform a Kubernetes API request.
+type Authorizer interface {
+	Authorize(ctx context.Context, u *User, attrs Attributes) (bool, error)
+}
+
+// SubjectAccessReviewAuthorizer asks the API server with a
+// SubjectAccessReview, so callers get exactly the access RBAC gives them in
+// the cluster. Decisions, allowed or not, are cached for the configured
+// TTL; failed API calls are not.
+type SubjectAccessReviewAuthorizer struct {
+	client kubernetes.Interface
+	cache  *ttlCache[string, bool]
+}
+
+// NewSubjectAccessReviewAuthorizer returns an authorizer backed by the API
+// server. A zero cacheTTL disables caching.
+func NewSubjectAccessReviewAuthorizer(client kubernetes.Interface, cacheTTL time.Duration) *SubjectAccessReviewAuthorizer {
+	return &SubjectAccessReviewAuthorizer{
+		client: client,
+		cache:  newTTLCache[string, bool](cacheTTL, defaultCacheSize),
+	}
+}
+
+func (a *SubjectAccessReviewAuthorizer) Authorize(ctx context.Context, u *User, attrs Attributes) (bool, error) {
+	key := decisionKey(u, attrs)
+	if allowed, ok := a.cache.get(key); ok {
+		return allowed, nil
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(u.Extra))
+	for k, v := range u.Extra {
+		extra[k] = v
+	}
+
+	review, err := a.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   u.Name,
+			UID:    u.UID,
+			Groups: u.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:        attrs.Verb,
+				Group:       attrs.Group,
+				Resource:    attrs.Resource,
+				Subresource: attrs.Subresource,
+				Namespace:   attrs.Namespace,
+				Name:        attrs.Name,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("subject access review failed: %w", err)
+	}
+
+	allowed := review.Status.Allowed && !review.Status.Denied
+	a.cache.set(key, allowed)
+	return allowed, nil
+}
+
+// decisionKey identifies a user and request for the decision cache.
+func decisionKey(u *User, attrs Attributes) string {
+	var sb strings.Builder
+	for _, part := range []string{u.Name, u.UID, attrs.Verb, attrs.Group, attrs.Resource, attrs.Subresource, attrs.Namespace, attrs.Name} {
+		sb.WriteString(part)
+		sb.WriteByte(0)
+	}
+
+	for _, g := range u.Groups {
+		sb.WriteString(g)
+		sb.WriteByte(0)
+	}
+
+	keys := make([]string, 0, len(u.Extra))
+	for k := range u.Extra {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		sb.WriteString(k + "=" + strings.Join(u.Extra[k], ","))
+		sb.WriteByte(0)
+	}
+
+	return sb.String()
+}
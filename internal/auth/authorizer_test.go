@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestSubjectAccessReviewAuthorizer(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := utils.Now
+	utils.Now = func() time.Time { return now }
+	defer func() { utils.Now = originalNow }()
+
+	calls := 0
+	apiDown := false
+	client := fake.NewClientset()
+	client.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		if apiDown {
+			return true, nil, errors.New("connection refused")
+		}
+
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "alice" && attrs.Namespace == "team-a" && attrs.Resource == "pods"
+		return true, review, nil
+	})
+
+	a := NewSubjectAccessReviewAuthorizer(client, 10*time.Second)
+	ctx := context.Background()
+	alice := &User{Name: "alice", Groups: []string{"developers"}}
+	listPods := Attributes{Verb: "list", Resource: "pods", Namespace: "team-a"}
+
+	allowed, err := a.Authorize(ctx, alice, listPods)
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	allowed, err = a.Authorize(ctx, alice, Attributes{Verb: "list", Resource: "pods"})
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	allowed, err = a.Authorize(ctx, &User{Name: "bob"}, listPods)
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.Equal(t, 3, calls)
+
+	// Both decisions are answered from the cache.
+	apiDown = true
+	allowed, err = a.Authorize(ctx, alice, listPods)
+	require.NoError(t, err)
+	require.True(t, allowed)
+	allowed, err = a.Authorize(ctx, &User{Name: "bob"}, listPods)
+	require.NoError(t, err)
+	require.False(t, allowed)
+	require.Equal(t, 3, calls)
+
+	// Once the entry expires the API is asked again, and failures are
+	// reported as errors rather than as denials.
+	now = now.Add(time.Minute)
+	_, err = a.Authorize(ctx, alice, listPods)
+	require.Error(t, err)
+	require.Equal(t, 4, calls)
+}
+
+func TestDecisionKey(t *testing.T) {
+	attrs := Attributes{Verb: "list", Resource: "pods", Namespace: "team-a"}
+	u := &User{Name: "alice", Groups: []string{"developers"}}
+
+	require.Equal(t, decisionKey(u, attrs), decisionKey(&User{Name: "alice", Groups: []string{"developers"}}, attrs))
+	require.NotEqual(t, decisionKey(u, attrs), decisionKey(&User{Name: "alice", Groups: []string{"admins"}}, attrs))
+
+	other := attrs
+	other.Namespace = "team-b"
+	require.NotEqual(t, decisionKey(u, attrs), decisionKey(u, other))
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"net/http"
+)
+
+// ClientCertificateUser returns the user of the verified client certificate
+// on r, if any. As in Kubernetes, the subject's common name is the user name
+// and its organizations are the groups. Certificates are only verified when
+// the server is configured with a client CA bundle.
+func ClientCertificateUser(r *http.Request) (*User, bool) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+
+	subject := r.TLS.VerifiedChains[0][0].Subject
+	if subject.CommonName == "" {
+		return nil, false
+	}
+
+	return &User{Name: subject.CommonName, Groups: subject.Organization}, true
+}
+
+// ClientCertificateMiddleware requires a verified client certificate on
+// every request and stores its user in the request context. It is used when
+// client certificates are the only way callers authenticate.
+func ClientCertificateMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Authorization")
+
+		u, ok := ClientCertificateUser(r)
+		if !ok {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), u)))
+	})
+}
+
+// OptionalClientCertificateMiddleware stores the user of a verified client
+// certificate in the request context when one is sent and serves requests
+// without one anonymously. It is used when client certificates are optional
+// and the only way callers authenticate.
+func OptionalClientCertificateMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Authorization")
+
+		if u, ok := ClientCertificateUser(r); ok {
+			r = r.WithContext(WithUser(r.Context(), u))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Middleware requires a valid "Authorization: Bearer <token>" header on
+// every request and stores the authenticated user in the request context.
+// Requests without a token may instead present a verified client
+// certificate. Invalid or missing tokens get 401; a token that could not be
+// checked, for example because the API server is down, gets 503.
+func Middleware(a Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Responses depend on who is asking once results are filtered per
+		// caller.
+		w.Header().Add("Vary", "Authorization")
+
+		token, ok := bearerToken(r)
+		if !ok {
+			if u, ok := ClientCertificateUser(r); ok {
+				next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), u)))
+				return
+			}
+			unauthorized(w)
+			return
+		}
+
+		u, err := a.AuthenticateToken(r.Context(), token)
+		if errors.Is(err, ErrUnauthenticated) {
+			unauthorized(w)
+			return
+		}
+		if err != nil {
+			slog.Error("failed to authenticate request", "path", r.URL.Path, "error", err)
+			http.Error(w, "authentication unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), u)))
+	})
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="cluster-telemetry"`)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
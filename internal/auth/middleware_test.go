@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type authenticatorFunc func(ctx context.Context, token string) (*User, error)
+
+func (f authenticatorFunc) AuthenticateToken(ctx context.Context, token string) (*User, error) {
+	return f(ctx, token)
+}
+
+func TestMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, ok := UserFrom(r.Context())
+		require.True(t, ok)
+		_, _ = w.Write([]byte(u.Name))
+	})
+
+	failing := authenticatorFunc(func(context.Context, string) (*User, error) {
+		return nil, errors.New("connection refused")
+	})
+
+	tests := []struct {
+		name          string
+		authenticator Authenticator
+		header        string
+		wantStatus    int
+		wantBody      string
+	}{
+		{name: "valid token", authenticator: NewStaticTokenAuthenticator("s3cret"), header: "Bearer s3cret", wantStatus: http.StatusOK, wantBody: StaticTokenUser},
+		{name: "scheme is case insensitive", authenticator: NewStaticTokenAuthenticator("s3cret"), header: "bearer s3cret", wantStatus: http.StatusOK, wantBody: StaticTokenUser},
+		{name: "missing header", authenticator: NewStaticTokenAuthenticator("s3cret"), wantStatus: http.StatusUnauthorized},
+		{name: "other scheme", authenticator: NewStaticTokenAuthenticator("s3cret"), header: "Basic czNjcmV0", wantStatus: http.StatusUnauthorized},
+		{name: "empty token", authenticator: NewStaticTokenAuthenticator("s3cret"), header: "Bearer ", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", authenticator: NewStaticTokenAuthenticator("s3cret"), header: "Bearer guess", wantStatus: http.StatusUnauthorized},
+		{name: "authenticator unavailable", authenticator: failing, header: "Bearer s3cret", wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/pods", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			Middleware(tt.authenticator, next).ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				require.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+			if tt.wantBody != "" {
+				require.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddlewareClientCertificate(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, ok := UserFrom(r.Context())
+		require.True(t, ok)
+		require.Equal(t, []string{"dashboards"}, u.Groups)
+		_, _ = w.Write([]byte(u.Name))
+	})
+
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+		{Subject: pkix.Name{CommonName: "dashboard", Organization: []string{"dashboards"}}},
+	}}}
+
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		tls        *tls.ConnectionState
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "certificate instead of a token", handler: Middleware(NewStaticTokenAuthenticator("s3cret"), next), tls: verified, wantStatus: http.StatusOK, wantBody: "dashboard"},
+		{name: "no token or certificate", handler: Middleware(NewStaticTokenAuthenticator("s3cret"), next), tls: &tls.ConnectionState{}, wantStatus: http.StatusUnauthorized},
+		{name: "certificate only", handler: ClientCertificateMiddleware(next), tls: verified, wantStatus: http.StatusOK, wantBody: "dashboard"},
+		{name: "certificate only without one", handler: ClientCertificateMiddleware(next), wantStatus: http.StatusUnauthorized},
+		{name: "optional certificate", handler: OptionalClientCertificateMiddleware(next), tls: verified, wantStatus: http.StatusOK, wantBody: "dashboard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/pods", nil)
+			req.TLS = tt.tls
+			rec := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantBody != "" {
+				require.Equal(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestOptionalClientCertificateMiddlewareWithoutCertificate(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := UserFrom(r.Context())
+		require.False(t, ok)
+		_, _ = w.Write([]byte("anonymous"))
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/pods", nil)
+	req.TLS = &tls.ConnectionState{}
+	rec := httptest.NewRecorder()
+
+	OptionalClientCertificateMiddleware(next).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "anonymous", rec.Body.String())
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TokenReviewAuthenticator validates tokens with the Kubernetes TokenReview
+// API, so any service account or user token the API server accepts works.
+// Results, including rejections, are cached for the configured TTL; failed
+// API calls are not.
+type TokenReviewAuthenticator struct {
+	client    kubernetes.Interface
+	audiences []string
+	cache     *ttlCache[[sha256.Size]byte, *User]
+}
+
+// NewTokenReviewAuthenticator returns an authenticator that asks the API
+// server about each token. When audiences is set, tokens must be valid for
+// at least one of them. A zero cacheTTL disables caching.
+func NewTokenReviewAuthenticator(client kubernetes.Interface, audiences []string, cacheTTL time.Duration) *TokenReviewAuthenticator {
+	return &TokenReviewAuthenticator{
+		client:    client,
+		audiences: audiences,
+		cache:     newTTLCache[[sha256.Size]byte, *User](cacheTTL, defaultCacheSize),
+	}
+}
+
+func (a *TokenReviewAuthenticator) AuthenticateToken(ctx context.Context, token string) (*User, error) {
+	// Keyed by hash so the cache does not hold tokens.
+	key := sha256.Sum256([]byte(token))
+	if u, ok := a.cache.get(key); ok {
+		if u == nil {
+			return nil, ErrUnauthenticated
+		}
+		return u, nil
+	}
+
+	review, err := a.client.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: a.audiences,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("token review failed: %w", err)
+	}
+
+	if !review.Status.Authenticated || !a.audienceMatches(review.Status.Audiences) {
+		a.cache.set(key, nil)
+		return nil, ErrUnauthenticated
+	}
+
+	u := userFromReview(review.Status.User)
+	a.cache.set(key, u)
+	return u, nil
+}
+
+func (a *TokenReviewAuthenticator) audienceMatches(got []string) bool {
+	if len(a.audiences) == 0 {
+		return true
+	}
+	for _, aud := range got {
+		if slices.Contains(a.audiences, aud) {
+			return true
+		}
+	}
+	return false
+}
+
+func userFromReview(info authenticationv1.UserInfo) *User {
+	u := &User{
+		Name:   info.Username,
+		UID:    info.UID,
+		Groups: info.Groups,
+	}
+
+	if len(info.Extra) > 0 {
+		u.Extra = make(map[string][]string, len(info.Extra))
+		for k, v := range info.Extra {
+			u.Extra[k] = v
+		}
+	}
+
+	return u
+}
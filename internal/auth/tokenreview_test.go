@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestTokenReviewAuthenticator(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := utils.Now
+	utils.Now = func() time.Time { return now }
+	defer func() { utils.Now = originalNow }()
+
+	calls := 0
+	apiDown := false
+	client := fake.NewClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		if apiDown {
+			return true, nil, errors.New("connection refused")
+		}
+
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		switch review.Spec.Token {
+		case "sa-token":
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:monitoring:dashboard",
+					Groups:   []string{"system:serviceaccounts"},
+				},
+				Audiences: review.Spec.Audiences,
+			}
+		case "other-audience":
+			review.Status = authenticationv1.TokenReviewStatus{Authenticated: true, Audiences: []string{"vault"}}
+		}
+		return true, review, nil
+	})
+
+	a := NewTokenReviewAuthenticator(client, []string{"cluster-telemetry"}, time.Minute)
+	ctx := context.Background()
+
+	u, err := a.AuthenticateToken(ctx, "sa-token")
+	require.NoError(t, err)
+	require.Equal(t, "system:serviceaccount:monitoring:dashboard", u.Name)
+	require.Equal(t, []string{"system:serviceaccounts"}, u.Groups)
+
+	_, err = a.AuthenticateToken(ctx, "expired")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+
+	_, err = a.AuthenticateToken(ctx, "other-audience")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+	require.Equal(t, 3, calls)
+
+	// Both accepted and rejected tokens are answered from the cache.
+	apiDown = true
+	_, err = a.AuthenticateToken(ctx, "sa-token")
+	require.NoError(t, err)
+	_, err = a.AuthenticateToken(ctx, "expired")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+	require.Equal(t, 3, calls)
+
+	// Once the entry expires the API is asked again, and failures are
+	// reported as errors rather than as rejected tokens.
+	now = now.Add(2 * time.Minute)
+	_, err = a.AuthenticateToken(ctx, "sa-token")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrUnauthenticated)
+	require.Equal(t, 4, calls)
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnauthenticated is returned by an Authenticator when the token is not
+// valid, as opposed to the token could not be checked.
+var ErrUnauthenticated = errors.New("invalid bearer token")
+
+// User is the identity a bearer token belongs to.
+type User struct {
+	Name   string              `json:"name"`
+	UID    string              `json:"uid,omitempty"`
+	Groups []string            `json:"groups,omitempty"`
+	Extra  map[string][]string `json:"extra,omitempty"`
+}
+
+// Authenticator resolves a bearer token to the user it belongs to.
+type Authenticator interface {
+	AuthenticateToken(ctx context.Context, token string) (*User, error)
+}
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying u.
+func WithUser(ctx context.Context, u *User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFrom returns the user the Middleware authenticated, if any.
+func UserFrom(ctx context.Context) (*User, bool) {
+	u, ok := ctx.Value(userKey{}).(*User)
+	return u, ok && u != nil
+}
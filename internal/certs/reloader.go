@@ -0,0 +1,160 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultReloadInterval is how often the files are checked for changes.
+const DefaultReloadInterval = 10 * time.Second
+
+// Reloader serves a certificate and key pair, and optionally a CA bundle to
+// verify client certificates against, from files on disk. The files are
+// re-read periodically so rotated certificates (e.g. by cert-manager) are
+// picked up without a restart.
+type Reloader struct {
+	certFile, keyFile, clientCAFile string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	checksum  [sha256.Size]byte
+}
+
+// NewReloader loads the files once and fails if they are not valid.
+// clientCAFile may be empty when client certificates are not verified.
+func NewReloader(certFile, keyFile, clientCAFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the files and swaps in the new certificate and CA bundle if
+// they changed. It reports whether anything was swapped. If the files are
+// invalid, for example because a rotation is half written, the previous
+// certificate stays in use and the error is returned.
+func (r *Reloader) Reload() (bool, error) {
+	certPEM, err := os.ReadFile(r.certFile)
+	if err != nil {
+		return false, fmt.Errorf("read certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("read key: %w", err)
+	}
+
+	var caPEM []byte
+	if r.clientCAFile != "" {
+		caPEM, err = os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return false, fmt.Errorf("read client CA bundle: %w", err)
+		}
+	}
+
+	checksum := sha256.Sum256(bytes.Join([][]byte{certPEM, keyPEM, caPEM}, []byte{0}))
+
+	r.mu.RLock()
+	unchanged := r.cert != nil && checksum == r.checksum
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, fmt.Errorf("load key pair: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caPEM) {
+			return false, fmt.Errorf("no certificates found in client CA bundle %s", r.clientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.checksum = checksum
+	r.mu.Unlock()
+
+	return true, nil
+}
+
+// Run reloads the files every interval until ctx is done.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				slog.Error("failed to reload TLS certificate, keeping the current one", "cert", r.certFile, "error", err)
+				continue
+			}
+			if reloaded {
+				slog.Info("reloaded TLS certificate", "cert", r.certFile, "notAfter", r.notAfter())
+			}
+		}
+	}
+}
+
+// TLSConfig returns a server config that always uses the latest certificate
+// and CA bundle. clientAuth only applies when a client CA bundle is set.
+func (r *Reloader) TLSConfig(clientAuth tls.ClientAuthType) *tls.Config {
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.getCertificate,
+		// http.Server only adds h2 to its own copy of the config, so the
+		// protocols are listed here for the per-client config below.
+		NextProtos: []string{"h2", "http/1.1"},
+	}
+	if r.clientCAFile == "" {
+		return cfg
+	}
+
+	// The CA bundle is looked up per handshake so rotated CAs apply to new
+	// connections.
+	base := cfg.Clone()
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		c := base.Clone()
+		c.ClientAuth = clientAuth
+		c.ClientCAs = r.clientCAs
+		return c, nil
+	}
+	return cfg
+}
+
+func (r *Reloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *Reloader) notAfter() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.cert == nil || r.cert.Leaf == nil {
+		return time.Time{}
+	}
+	return r.cert.Leaf.NotAfter
+}
@@ -0,0 +1,194 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert issues a certificate for cn, signed by parent or self-signed
+// as a CA when parent is nil.
+func newTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"dashboards"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+	}
+
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	_, err := NewReloader(certFile, keyFile, "")
+	require.Error(t, err)
+
+	ca := newTestCert(t, "ca", nil)
+	first := newTestCert(t, "server-1", ca)
+	writeFile(t, certFile, first.certPEM)
+	writeFile(t, keyFile, first.keyPEM)
+
+	r, err := NewReloader(certFile, keyFile, "")
+	require.NoError(t, err)
+
+	got, err := r.getCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "server-1", got.Leaf.Subject.CommonName)
+
+	reloaded, err := r.Reload()
+	require.NoError(t, err)
+	require.False(t, reloaded)
+
+	// A rotation caught half way keeps serving the previous certificate.
+	second := newTestCert(t, "server-2", ca)
+	writeFile(t, certFile, second.certPEM)
+	_, err = r.Reload()
+	require.Error(t, err)
+
+	got, err = r.getCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "server-1", got.Leaf.Subject.CommonName)
+
+	writeFile(t, keyFile, second.keyPEM)
+	reloaded, err = r.Reload()
+	require.NoError(t, err)
+	require.True(t, reloaded)
+
+	got, err = r.getCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, "server-2", got.Leaf.Subject.CommonName)
+}
+
+func TestReloaderClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "localhost", ca)
+	writeFile(t, certFile, server.certPEM)
+	writeFile(t, keyFile, server.keyPEM)
+	writeFile(t, caFile, ca.certPEM)
+
+	r, err := NewReloader(certFile, keyFile, caFile)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, req.TLS.VerifiedChains[0][0].Subject.CommonName)
+	}))
+	srv.TLS = r.TLSConfig(tls.RequireAndVerifyClientCert)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	get := func(clientCert *testCert) (string, error) {
+		cfg := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+		if clientCert != nil {
+			pair, err := tls.X509KeyPair(clientCert.certPEM, clientCert.keyPEM)
+			require.NoError(t, err)
+			cfg.Certificates = []tls.Certificate{pair}
+		}
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg, ForceAttemptHTTP2: true}}
+		defer client.CloseIdleConnections()
+
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		// Verifying client certificates must not cost HTTP/2.
+		require.Equal(t, 2, resp.ProtoMajor)
+
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	body, err := get(newTestCert(t, "dashboard", ca))
+	require.NoError(t, err)
+	require.Equal(t, "dashboard", body)
+
+	_, err = get(nil)
+	require.Error(t, err)
+
+	// Certificates from a CA that is not in the bundle are rejected until
+	// the bundle is rotated to include it.
+	otherCA := newTestCert(t, "other-ca", nil)
+	client := newTestCert(t, "dashboard", otherCA)
+	_, err = get(client)
+	require.Error(t, err)
+
+	writeFile(t, caFile, append(append([]byte{}, ca.certPEM...), otherCA.certPEM...))
+	reloaded, err := r.Reload()
+	require.NoError(t, err)
+	require.True(t, reloaded)
+
+	body, err = get(client)
+	require.NoError(t, err)
+	require.Equal(t, "dashboard", body)
+}
@@ -12,30 +12,83 @@ import (
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
-func NewKubeConfig() (*rest.Config, error) {
-	// Try in-cluster first
-	cfg, err := rest.InClusterConfig()
-	if err == nil {
-		slog.Info("Using in-cluster Kubernetes config")
-		return cfg, nil
-	}
-
-	slog.Warn("In-cluster config failed, falling back to kubeconfig",
-		"error", err,
-		"host", os.Getenv("KUBERNETES_SERVICE_HOST"),
-		"port", os.Getenv("KUBERNETES_SERVICE_PORT"),
-	)
+// KubeOptions select the cluster to connect to and tune the client.
+type KubeOptions struct {
+	// Kubeconfig is a kubeconfig path, or a list of them separated like
+	// KUBECONFIG, used instead of the in-cluster config.
+	Kubeconfig string
+	// Context overrides the kubeconfig's current context.
+	Context string
 
-	// Fallback to local kubeconfig
-	home, _ := os.UserHomeDir()
-	kubeconfig := filepath.Join(home, ".kube", "config")
+	QPS       float32
+	Burst     int
+	UserAgent string
+}
 
-	cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+// NewKubeConfig returns the client config for opts. Without a kubeconfig or
+// context it uses the in-cluster config and falls back to the KUBECONFIG
+// environment variable or ~/.kube/config.
+func NewKubeConfig(opts KubeOptions) (*rest.Config, error) {
+	cfg, err := loadKubeConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.QPS = opts.QPS
+	cfg.Burst = opts.Burst
+	if opts.UserAgent != "" {
+		cfg.UserAgent = opts.UserAgent
+	}
+
+	return cfg, nil
+}
+
+func loadKubeConfig(opts KubeOptions) (*rest.Config, error) {
+	if opts.Kubeconfig == "" && opts.Context == "" {
+		// Try in-cluster first
+		cfg, err := rest.InClusterConfig()
+		if err == nil {
+			slog.Info("Using in-cluster Kubernetes config")
+			return cfg, nil
+		}
+
+		slog.Warn("In-cluster config failed, falling back to kubeconfig",
+			"error", err,
+			"host", os.Getenv("KUBERNETES_SERVICE_HOST"),
+			"port", os.Getenv("KUBERNETES_SERVICE_PORT"),
+		)
+	}
+
+	// The default rules read KUBECONFIG, merging every file in the list,
+	// and fall back to ~/.kube/config.
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if opts.Kubeconfig != "" {
+		paths := filepath.SplitList(opts.Kubeconfig)
+		if len(paths) == 1 {
+			rules.ExplicitPath = paths[0]
+		} else {
+			rules.Precedence = paths
+		}
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		rules,
+		&clientcmd.ConfigOverrides{CurrentContext: opts.Context},
+	)
+
+	cfg, err := clientConfig.ClientConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
 
-	slog.Info("Using local kubeconfig", "path", kubeconfig)
+	kubeContext := opts.Context
+	if kubeContext == "" {
+		if raw, err := clientConfig.RawConfig(); err == nil {
+			kubeContext = raw.CurrentContext
+		}
+	}
+
+	slog.Info("Using local kubeconfig", "paths", rules.GetLoadingPrecedence(), "context", kubeContext)
 	return cfg, nil
 }
 
@@ -0,0 +1,115 @@
+package clients
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const stagingKubeconfig = `apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.com
+contexts:
+- name: staging
+  context:
+    cluster: staging
+    user: dev
+users:
+- name: dev
+  user:
+    token: staging-token
+`
+
+const productionKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: production
+  cluster:
+    server: https://production.example.com
+contexts:
+- name: production
+  context:
+    cluster: production
+    user: dev
+users:
+- name: dev
+  user:
+    token: production-token
+`
+
+func writeKubeconfig(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestNewKubeConfig(t *testing.T) {
+	dir := t.TempDir()
+	staging := writeKubeconfig(t, dir, "staging", stagingKubeconfig)
+	production := writeKubeconfig(t, dir, "production", productionKubeconfig)
+	merged := staging + string(os.PathListSeparator) + production
+
+	tests := []struct {
+		name     string
+		opts     KubeOptions
+		wantHost string
+		wantErr  string
+	}{
+		{
+			name:     "current context",
+			opts:     KubeOptions{Kubeconfig: staging},
+			wantHost: "https://staging.example.com",
+		},
+		{
+			name:     "context from a merged list",
+			opts:     KubeOptions{Kubeconfig: merged, Context: "production"},
+			wantHost: "https://production.example.com",
+		},
+		{
+			name:    "unknown context",
+			opts:    KubeOptions{Kubeconfig: merged, Context: "dev"},
+			wantErr: `context "dev" does not exist`,
+		},
+		{
+			name:    "missing explicit file",
+			opts:    KubeOptions{Kubeconfig: filepath.Join(dir, "missing")},
+			wantErr: "failed to load kubeconfig",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewKubeConfig(tt.opts)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantHost, cfg.Host)
+		})
+	}
+}
+
+func TestNewKubeConfigClientSettings(t *testing.T) {
+	path := writeKubeconfig(t, t.TempDir(), "config", stagingKubeconfig)
+
+	cfg, err := NewKubeConfig(KubeOptions{Kubeconfig: path, QPS: 50, Burst: 100, UserAgent: "cluster-telemetry/test"})
+	require.NoError(t, err)
+	require.Equal(t, float32(50), cfg.QPS)
+	require.Equal(t, 100, cfg.Burst)
+	require.Equal(t, "cluster-telemetry/test", cfg.UserAgent)
+
+	// client-go fills in its own user agent when none is set.
+	cfg, err = NewKubeConfig(KubeOptions{Kubeconfig: path, QPS: 5, Burst: 10})
+	require.NoError(t, err)
+	require.Empty(t, cfg.UserAgent)
+}
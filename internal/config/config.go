@@ -0,0 +1,260 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/certs"
+	"github.com/JNickson/cluster-telemetry-service/internal/history"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	AuthModeNone        = "none"
+	AuthModeTokenReview = "tokenreview"
+	AuthModeStatic      = "static"
+
+	TLSClientAuthRequire  = "require"
+	TLSClientAuthOptional = "optional"
+)
+
+// Config is the service configuration. Durations are written as Go
+// durations (e.g. "30s") in the YAML file.
+type Config struct {
+	Server  ServerConfig  `json:"server"`
+	Kube    KubeConfig    `json:"kube"`
+	Refresh RefreshConfig `json:"refresh"`
+	Metrics MetricsConfig `json:"metrics"`
+	History HistoryConfig `json:"history"`
+	Summary SummaryConfig `json:"summary"`
+	Store   StoreConfig   `json:"store"`
+	Auth    AuthConfig    `json:"auth"`
+	Authz   AuthzConfig   `json:"authz"`
+	TLS     TLSConfig     `json:"tls"`
+	Logs    LogsConfig    `json:"logs"`
+}
+
+type ServerConfig struct {
+	Port            int             `json:"port"`
+	ShutdownTimeout metav1.Duration `json:"shutdownTimeout"`
+}
+
+type KubeConfig struct {
+	// Kubeconfig and Context are used instead of the in-cluster config when
+	// set. Kubeconfig may list several files like KUBECONFIG.
+	Kubeconfig string `json:"kubeconfig"`
+	Context    string `json:"context"`
+
+	// QPS and Burst rate limit requests to the API server.
+	QPS   float32 `json:"qps"`
+	Burst int     `json:"burst"`
+	// UserAgent defaults to client-go's when empty.
+	UserAgent string `json:"userAgent"`
+}
+
+type RefreshConfig struct {
+	Interval metav1.Duration `json:"interval"`
+	Debounce metav1.Duration `json:"debounce"`
+
+	// ReadinessMaxStaleness defaults to three intervals.
+	ReadinessMaxStaleness metav1.Duration `json:"readinessMaxStaleness"`
+}
+
+type MetricsConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+type HistoryConfig struct {
+	Retention  metav1.Duration `json:"retention"`
+	Resolution metav1.Duration `json:"resolution"`
+}
+
+type SummaryConfig struct {
+	// NodePoolLabel groups nodes into pools; empty disables the breakdown.
+	NodePoolLabel string `json:"nodePoolLabel"`
+}
+
+type StoreConfig struct {
+	// Path keeps the store in memory only when empty.
+	Path            string          `json:"path"`
+	Retention       metav1.Duration `json:"retention"`
+	CompactInterval metav1.Duration `json:"compactInterval"`
+}
+
+type AuthConfig struct {
+	Mode        string          `json:"mode"`
+	CacheTTL    metav1.Duration `json:"cacheTTL"`
+	Audiences   []string        `json:"audiences"`
+	StaticToken string          `json:"staticToken"`
+}
+
+type AuthzConfig struct {
+	Enabled  bool            `json:"enabled"`
+	CacheTTL metav1.Duration `json:"cacheTTL"`
+}
+
+type TLSConfig struct {
+	CertFile       string          `json:"certFile"`
+	KeyFile        string          `json:"keyFile"`
+	ClientCAFile   string          `json:"clientCAFile"`
+	ClientAuth     string          `json:"clientAuth"`
+	ReloadInterval metav1.Duration `json:"reloadInterval"`
+}
+
+type LogsConfig struct {
+	// RetryDelay is the wait before reopening a failed log stream.
+	RetryDelay metav1.Duration `json:"retryDelay"`
+	// ReconcileInterval is how often the set of streamed pods is
+	// re-evaluated.
+	ReconcileInterval metav1.Duration `json:"reconcileInterval"`
+	Stream            StreamConfig    `json:"stream"`
+}
+
+// StreamConfig bounds the frequencyMs option of the log stream.
+type StreamConfig struct {
+	DefaultFrequency metav1.Duration `json:"defaultFrequency"`
+	MinFrequency     metav1.Duration `json:"minFrequency"`
+	MaxFrequency     metav1.Duration `json:"maxFrequency"`
+}
+
+// Default returns the configuration used when nothing is set.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:            8001,
+			ShutdownTimeout: duration(5 * time.Second),
+		},
+		Kube: KubeConfig{
+			QPS:   rest.DefaultQPS,
+			Burst: rest.DefaultBurst,
+		},
+		Refresh: RefreshConfig{
+			Interval: duration(30 * time.Second),
+			Debounce: duration(time.Second),
+		},
+		Metrics: MetricsConfig{Enabled: true},
+		History: HistoryConfig{
+			Retention:  duration(history.DefaultRetention),
+			Resolution: duration(history.DefaultResolution),
+		},
+		Summary: SummaryConfig{NodePoolLabel: "node.kubernetes.io/instance-type"},
+		Store: StoreConfig{
+			Retention:       duration(24 * time.Hour),
+			CompactInterval: duration(5 * time.Minute),
+		},
+		Auth: AuthConfig{
+			Mode:     AuthModeNone,
+			CacheTTL: duration(time.Minute),
+		},
+		Authz: AuthzConfig{
+			Enabled:  true,
+			CacheTTL: duration(10 * time.Second),
+		},
+		TLS: TLSConfig{
+			ClientAuth:     TLSClientAuthRequire,
+			ReloadInterval: duration(certs.DefaultReloadInterval),
+		},
+		Logs: LogsConfig{
+			RetryDelay:        duration(2 * time.Second),
+			ReconcileInterval: duration(5 * time.Second),
+			Stream: StreamConfig{
+				DefaultFrequency: duration(500 * time.Millisecond),
+				MinFrequency:     duration(100 * time.Millisecond),
+				MaxFrequency:     duration(10 * time.Second),
+			},
+		},
+	}
+}
+
+// complete fills in the settings derived from others.
+func (c *Config) complete() {
+	if c.Refresh.ReadinessMaxStaleness.Duration == 0 {
+		// A quiet cluster still refreshes every interval, so a few missed
+		// intervals mean refreshes are failing.
+		c.Refresh.ReadinessMaxStaleness = duration(3 * c.Refresh.Interval.Duration)
+	}
+}
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		fail("server.port must be between 1 and 65535")
+	}
+
+	if c.Kube.QPS <= 0 {
+		fail("kube.qps must be positive")
+	}
+	if c.Kube.Burst < 1 {
+		fail("kube.burst must be at least 1")
+	}
+
+	for _, d := range []struct {
+		key   string
+		value metav1.Duration
+	}{
+		{"server.shutdownTimeout", c.Server.ShutdownTimeout},
+		{"refresh.interval", c.Refresh.Interval},
+		{"refresh.debounce", c.Refresh.Debounce},
+		{"refresh.readinessMaxStaleness", c.Refresh.ReadinessMaxStaleness},
+		{"history.retention", c.History.Retention},
+		{"history.resolution", c.History.Resolution},
+		{"store.retention", c.Store.Retention},
+		{"store.compactInterval", c.Store.CompactInterval},
+		{"auth.cacheTTL", c.Auth.CacheTTL},
+		{"authz.cacheTTL", c.Authz.CacheTTL},
+		{"tls.reloadInterval", c.TLS.ReloadInterval},
+		{"logs.retryDelay", c.Logs.RetryDelay},
+		{"logs.reconcileInterval", c.Logs.ReconcileInterval},
+		{"logs.stream.defaultFrequency", c.Logs.Stream.DefaultFrequency},
+		{"logs.stream.minFrequency", c.Logs.Stream.MinFrequency},
+		{"logs.stream.maxFrequency", c.Logs.Stream.MaxFrequency},
+	} {
+		if d.value.Duration <= 0 {
+			fail("%s must be positive", d.key)
+		}
+	}
+
+	if c.History.Resolution.Duration > c.History.Retention.Duration {
+		fail("history.resolution must not exceed history.retention")
+	}
+
+	switch c.Auth.Mode {
+	case AuthModeNone, AuthModeTokenReview:
+	case AuthModeStatic:
+		if c.Auth.StaticToken == "" {
+			fail("auth.staticToken required when auth.mode is %s", AuthModeStatic)
+		}
+	default:
+		fail("invalid auth.mode: %s (expected %s, %s or %s)", c.Auth.Mode, AuthModeNone, AuthModeTokenReview, AuthModeStatic)
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		fail("tls.certFile and tls.keyFile must be set together")
+	}
+	if c.TLS.ClientCAFile != "" && c.TLS.CertFile == "" {
+		fail("tls.clientCAFile requires tls.certFile and tls.keyFile")
+	}
+	if c.TLS.ClientAuth != TLSClientAuthRequire && c.TLS.ClientAuth != TLSClientAuthOptional {
+		fail("invalid tls.clientAuth: %s (expected %s or %s)", c.TLS.ClientAuth, TLSClientAuthRequire, TLSClientAuthOptional)
+	}
+
+	stream := c.Logs.Stream
+	if stream.MinFrequency.Duration > stream.MaxFrequency.Duration {
+		fail("logs.stream.minFrequency must not exceed logs.stream.maxFrequency")
+	} else if stream.DefaultFrequency.Duration < stream.MinFrequency.Duration || stream.DefaultFrequency.Duration > stream.MaxFrequency.Duration {
+		fail("logs.stream.defaultFrequency must be between logs.stream.minFrequency and logs.stream.maxFrequency")
+	}
+
+	return errors.Join(errs...)
+}
+
+func duration(d time.Duration) metav1.Duration {
+	return metav1.Duration{Duration: d}
+}
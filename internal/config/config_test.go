@@ -0,0 +1,203 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func envFrom(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, printConfig, err := Load(nil, envFrom(nil))
+	require.NoError(t, err)
+	require.False(t, printConfig)
+
+	want := Default()
+	want.Refresh.ReadinessMaxStaleness = duration(90 * time.Second)
+	require.Equal(t, want, cfg)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfigFile(t, `
+server:
+  port: 9000
+refresh:
+  interval: 1m
+  debounce: 2s
+metrics:
+  enabled: false
+logs:
+  retryDelay: 3s
+`)
+
+	cfg, _, err := Load(
+		[]string{"--config", path, "--refresh-debounce", "500ms", "--metrics-enabled", "--context", "production"},
+		envFrom(map[string]string{
+			"KUBECONFIG":       "/home/dev/.kube/staging:/home/dev/.kube/production",
+			"KUBE_API_QPS":     "50",
+			"REFRESH_INTERVAL": "45s",
+			"REFRESH_DEBOUNCE": "4s",
+			"AUTH_AUDIENCES":   "cluster-telemetry, vault",
+		}),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, 9000, cfg.Server.Port, "file over default")
+	require.Equal(t, 45*time.Second, cfg.Refresh.Interval.Duration, "env over file")
+	require.Equal(t, 500*time.Millisecond, cfg.Refresh.Debounce.Duration, "flag over env")
+	require.True(t, cfg.Metrics.Enabled, "bool flag without a value")
+	require.Equal(t, 3*time.Second, cfg.Logs.RetryDelay.Duration)
+	require.Equal(t, "/home/dev/.kube/staging:/home/dev/.kube/production", cfg.Kube.Kubeconfig)
+	require.Equal(t, "production", cfg.Kube.Context)
+	require.Equal(t, float32(50), cfg.Kube.QPS)
+	require.Equal(t, []string{"cluster-telemetry", "vault"}, cfg.Auth.Audiences)
+	require.Equal(t, 135*time.Second, cfg.Refresh.ReadinessMaxStaleness.Duration, "derived from the final interval")
+}
+
+func TestLoadConfigFileFromEnv(t *testing.T) {
+	path := writeConfigFile(t, "store:\n  path: /var/lib/telemetry/store.log\n")
+
+	cfg, _, err := Load(nil, envFrom(map[string]string{"CONFIG_FILE": path}))
+	require.NoError(t, err)
+	require.Equal(t, "/var/lib/telemetry/store.log", cfg.Store.Path)
+}
+
+func TestLoadEmptyEnv(t *testing.T) {
+	cfg, _, err := Load(nil, envFrom(map[string]string{
+		"REFRESH_INTERVAL": "",
+		"NODE_POOL_LABEL":  "",
+	}))
+	require.NoError(t, err)
+
+	require.Equal(t, 30*time.Second, cfg.Refresh.Interval.Duration, "empty keeps the default")
+	require.Empty(t, cfg.Summary.NodePoolLabel, "empty disables the node pool breakdown")
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		env         map[string]string
+		file        string
+		errContains []string
+	}{
+		{
+			name:        "invalid env duration",
+			env:         map[string]string{"REFRESH_INTERVAL": "soon"},
+			errContains: []string{"invalid REFRESH_INTERVAL"},
+		},
+		{
+			name:        "negative env duration",
+			env:         map[string]string{"REFRESH_INTERVAL": "-1s"},
+			errContains: []string{"invalid REFRESH_INTERVAL: must be positive"},
+		},
+		{
+			name:        "invalid env bool",
+			env:         map[string]string{"METRICS_ENABLED": "maybe"},
+			errContains: []string{"invalid METRICS_ENABLED"},
+		},
+		{
+			name:        "invalid flag",
+			args:        []string{"--port", "http"},
+			errContains: []string{"invalid --port"},
+		},
+		{
+			name:        "zero qps",
+			args:        []string{"--kube-api-qps", "0"},
+			errContains: []string{"kube.qps must be positive"},
+		},
+		{
+			name:        "unknown flag",
+			args:        []string{"--refresh", "1m"},
+			errContains: []string{"flag provided but not defined"},
+		},
+		{
+			name:        "unknown file key",
+			file:        "refresh:\n  intervall: 1m\n",
+			errContains: []string{"unknown field"},
+		},
+		{
+			name: "every invalid setting is reported",
+			file: "server:\n  port: 0\nhistory:\n  retention: 1m\n  resolution: 5m\nauth:\n  mode: static\n",
+			errContains: []string{
+				"server.port must be between 1 and 65535",
+				"history.resolution must not exceed history.retention",
+				"auth.staticToken required",
+			},
+		},
+		{
+			name:        "zero duration in file",
+			file:        "refresh:\n  debounce: 0s\n",
+			errContains: []string{"refresh.debounce must be positive"},
+		},
+		{
+			name:        "tls key without certificate",
+			env:         map[string]string{"TLS_KEY_FILE": "/etc/tls/tls.key"},
+			errContains: []string{"tls.certFile and tls.keyFile must be set together"},
+		},
+		{
+			name:        "stream default outside bounds",
+			args:        []string{"--logs-stream-default-frequency", "50ms"},
+			errContains: []string{"logs.stream.defaultFrequency must be between"},
+		},
+		{
+			name:        "static token cannot be a flag",
+			args:        []string{"--auth-static-token", "s3cret"},
+			errContains: []string{"flag provided but not defined"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"--config", writeConfigFile(t, tt.file)}, args...)
+			}
+
+			_, _, err := Load(args, envFrom(tt.env))
+			require.Error(t, err)
+			for _, want := range tt.errContains {
+				require.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	cfg, printConfig, err := Load(
+		[]string{"--print-config", "--auth-mode", "static"},
+		envFrom(map[string]string{"AUTH_STATIC_TOKEN": "s3cret"}),
+	)
+	require.NoError(t, err)
+	require.True(t, printConfig)
+
+	var out bytes.Buffer
+	require.NoError(t, Print(&out, cfg))
+	require.NotContains(t, out.String(), "s3cret")
+	require.Contains(t, out.String(), "staticToken: <redacted>")
+	require.Contains(t, out.String(), "interval: 30s")
+
+	// The printed configuration loads back to the same values.
+	path := writeConfigFile(t, out.String())
+	reloaded, _, err := Load([]string{"--config", path}, envFrom(map[string]string{"AUTH_STATIC_TOKEN": "s3cret"}))
+	require.NoError(t, err)
+	require.Equal(t, cfg, reloaded)
+}
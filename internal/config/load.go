@@ -0,0 +1,248 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// setting binds one field of Config to its environment variable and flag.
+// A setting without a flag can only come from the file or the environment.
+type setting struct {
+	env    string
+	flag   string
+	usage  string
+	isBool bool
+	// emptyAllowed makes an empty environment variable set the field to
+	// empty rather than leave the default.
+	emptyAllowed bool
+	set          func(c *Config, raw string) error
+}
+
+var settings = []setting{
+	intSetting("PORT", "port", "port to listen on", func(c *Config) *int { return &c.Server.Port }),
+	durationSetting("SHUTDOWN_TIMEOUT", "shutdown-timeout", "time to wait for open requests on shutdown", func(c *Config) *metav1.Duration { return &c.Server.ShutdownTimeout }),
+	stringSetting("KUBECONFIG", "kubeconfig", "kubeconfig file, or list of files, to use instead of the in-cluster config", func(c *Config) *string { return &c.Kube.Kubeconfig }),
+	stringSetting("KUBE_CONTEXT", "context", "kubeconfig context to use instead of the current one", func(c *Config) *string { return &c.Kube.Context }),
+	float32Setting("KUBE_API_QPS", "kube-api-qps", "requests per second to the API server", func(c *Config) *float32 { return &c.Kube.QPS }),
+	intSetting("KUBE_API_BURST", "kube-api-burst", "request burst allowed above kube-api-qps", func(c *Config) *int { return &c.Kube.Burst }),
+	stringSetting("KUBE_USER_AGENT", "user-agent", "user agent sent to the API server", func(c *Config) *string { return &c.Kube.UserAgent }),
+
+	durationSetting("REFRESH_INTERVAL", "refresh-interval", "full snapshot rebuild interval", func(c *Config) *metav1.Duration { return &c.Refresh.Interval }),
+	durationSetting("REFRESH_DEBOUNCE", "refresh-debounce", "window informer changes are coalesced over", func(c *Config) *metav1.Duration { return &c.Refresh.Debounce }),
+	durationSetting("READINESS_MAX_STALENESS", "readiness-max-staleness", "snapshot age after which /readyz fails (default 3 refresh intervals)", func(c *Config) *metav1.Duration { return &c.Refresh.ReadinessMaxStaleness }),
+	boolSetting("METRICS_ENABLED", "metrics-enabled", "collect usage from metrics.k8s.io", func(c *Config) *bool { return &c.Metrics.Enabled }),
+
+	durationSetting("HISTORY_RETENTION", "history-retention", "how long samples are kept", func(c *Config) *metav1.Duration { return &c.History.Retention }),
+	durationSetting("HISTORY_RESOLUTION", "history-resolution", "interval between kept samples", func(c *Config) *metav1.Duration { return &c.History.Resolution }),
+	{
+		env:          "NODE_POOL_LABEL",
+		flag:         "node-pool-label",
+		usage:        "node label the summary groups node pools by; empty disables the breakdown",
+		emptyAllowed: true,
+		set:          func(c *Config, raw string) error { c.Summary.NodePoolLabel = raw; return nil },
+	},
+
+	stringSetting("STORE_PATH", "store-path", "file to persist the store to; empty keeps it in memory", func(c *Config) *string { return &c.Store.Path }),
+	durationSetting("STORE_RETENTION", "store-retention", "age after which persisted records are ignored", func(c *Config) *metav1.Duration { return &c.Store.Retention }),
+	durationSetting("STORE_COMPACT_INTERVAL", "store-compact-interval", "interval between store log compactions", func(c *Config) *metav1.Duration { return &c.Store.CompactInterval }),
+
+	stringSetting("AUTH_MODE", "auth-mode", "none, tokenreview or static", func(c *Config) *string { return &c.Auth.Mode }),
+	durationSetting("AUTH_CACHE_TTL", "auth-cache-ttl", "how long TokenReview results are cached", func(c *Config) *metav1.Duration { return &c.Auth.CacheTTL }),
+	listSetting("AUTH_AUDIENCES", "auth-audiences", "comma-separated audiences tokens must be valid for", func(c *Config) *[]string { return &c.Auth.Audiences }),
+	// No flag: command lines are visible to every process on the host.
+	stringSetting("AUTH_STATIC_TOKEN", "", "", func(c *Config) *string { return &c.Auth.StaticToken }),
+	boolSetting("AUTHZ_ENABLED", "authz-enabled", "authorize callers with SubjectAccessReview", func(c *Config) *bool { return &c.Authz.Enabled }),
+	durationSetting("AUTHZ_CACHE_TTL", "authz-cache-ttl", "how long authorization decisions are cached", func(c *Config) *metav1.Duration { return &c.Authz.CacheTTL }),
+
+	stringSetting("TLS_CERT_FILE", "tls-cert-file", "certificate file to serve HTTPS with", func(c *Config) *string { return &c.TLS.CertFile }),
+	stringSetting("TLS_KEY_FILE", "tls-key-file", "key file of the certificate", func(c *Config) *string { return &c.TLS.KeyFile }),
+	stringSetting("TLS_CLIENT_CA_FILE", "tls-client-ca-file", "CA bundle to verify client certificates against", func(c *Config) *string { return &c.TLS.ClientCAFile }),
+	stringSetting("TLS_CLIENT_AUTH", "tls-client-auth", "require or optional", func(c *Config) *string { return &c.TLS.ClientAuth }),
+	durationSetting("TLS_RELOAD_INTERVAL", "tls-reload-interval", "how often certificate files are checked for changes", func(c *Config) *metav1.Duration { return &c.TLS.ReloadInterval }),
+
+	durationSetting("LOGS_RETRY_DELAY", "logs-retry-delay", "wait before reopening a failed log stream", func(c *Config) *metav1.Duration { return &c.Logs.RetryDelay }),
+	durationSetting("LOGS_RECONCILE_INTERVAL", "logs-reconcile-interval", "how often streamed pods are re-evaluated", func(c *Config) *metav1.Duration { return &c.Logs.ReconcileInterval }),
+	durationSetting("LOGS_STREAM_DEFAULT_FREQUENCY", "logs-stream-default-frequency", "log stream emit interval when frequencyMs is not set", func(c *Config) *metav1.Duration { return &c.Logs.Stream.DefaultFrequency }),
+	durationSetting("LOGS_STREAM_MIN_FREQUENCY", "logs-stream-min-frequency", "smallest frequencyMs accepted", func(c *Config) *metav1.Duration { return &c.Logs.Stream.MinFrequency }),
+	durationSetting("LOGS_STREAM_MAX_FREQUENCY", "logs-stream-max-frequency", "largest frequencyMs accepted", func(c *Config) *metav1.Duration { return &c.Logs.Stream.MaxFrequency }),
+}
+
+// Load builds the configuration from, in increasing order of precedence,
+// the defaults, the YAML file given by --config or CONFIG_FILE, environment
+// variables and command line flags, and validates it. printConfig reports
+// whether --print-config was passed.
+func Load(args []string, lookupEnv func(string) (string, bool)) (cfg Config, printConfig bool, err error) {
+	fs := flag.NewFlagSet("cluster-telemetry-service", flag.ContinueOnError)
+
+	configFile, _ := lookupEnv("CONFIG_FILE")
+	fs.StringVar(&configFile, "config", configFile, "YAML configuration file (env CONFIG_FILE)")
+	fs.BoolVar(&printConfig, "print-config", false, "print the effective configuration and exit")
+
+	// Flags are applied last, so their values are recorded while parsing.
+	type override struct {
+		setting setting
+		raw     string
+	}
+	var overrides []override
+
+	for _, s := range settings {
+		if s.flag == "" {
+			continue
+		}
+
+		usage := fmt.Sprintf("%s (env %s)", s.usage, s.env)
+		record := func(raw string) error {
+			overrides = append(overrides, override{setting: s, raw: raw})
+			return nil
+		}
+		if s.isBool {
+			fs.BoolFunc(s.flag, usage, record)
+		} else {
+			fs.Func(s.flag, usage, record)
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, false, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, false, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	cfg = Default()
+
+	if configFile != "" {
+		if err := loadFile(&cfg, configFile); err != nil {
+			return Config{}, false, err
+		}
+	}
+
+	for _, s := range settings {
+		raw, ok := lookupEnv(s.env)
+		if !ok || (raw == "" && !s.emptyAllowed) {
+			continue
+		}
+		if err := s.set(&cfg, raw); err != nil {
+			return Config{}, false, fmt.Errorf("invalid %s: %w", s.env, err)
+		}
+	}
+
+	for _, o := range overrides {
+		if err := o.setting.set(&cfg, o.raw); err != nil {
+			return Config{}, false, fmt.Errorf("invalid --%s: %w", o.setting.flag, err)
+		}
+	}
+
+	cfg.complete()
+	if err := cfg.Validate(); err != nil {
+		return Config{}, false, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, printConfig, nil
+}
+
+// loadFile merges the YAML file at path over cfg. Unknown keys are rejected
+// so typos do not silently fall back to defaults.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Print writes cfg as YAML in the file format, with secrets redacted.
+func Print(w io.Writer, cfg Config) error {
+	if cfg.Auth.StaticToken != "" {
+		cfg.Auth.StaticToken = "<redacted>"
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+func intSetting(env, flag, usage string, field func(*Config) *int) setting {
+	return setting{env: env, flag: flag, usage: usage, set: func(c *Config, raw string) error {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		*field(c) = v
+		return nil
+	}}
+}
+
+func float32Setting(env, flag, usage string, field func(*Config) *float32) setting {
+	return setting{env: env, flag: flag, usage: usage, set: func(c *Config, raw string) error {
+		v, err := strconv.ParseFloat(raw, 32)
+		if err != nil {
+			return err
+		}
+		*field(c) = float32(v)
+		return nil
+	}}
+}
+
+func boolSetting(env, flag, usage string, field func(*Config) *bool) setting {
+	return setting{env: env, flag: flag, usage: usage, isBool: true, set: func(c *Config, raw string) error {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		*field(c) = v
+		return nil
+	}}
+}
+
+func durationSetting(env, flag, usage string, field func(*Config) *metav1.Duration) setting {
+	return setting{env: env, flag: flag, usage: usage, set: func(c *Config, raw string) error {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		if d <= 0 {
+			return errors.New("must be positive")
+		}
+		*field(c) = metav1.Duration{Duration: d}
+		return nil
+	}}
+}
+
+func stringSetting(env, flag, usage string, field func(*Config) *string) setting {
+	return setting{env: env, flag: flag, usage: usage, set: func(c *Config, raw string) error {
+		*field(c) = raw
+		return nil
+	}}
+}
+
+func listSetting(env, flag, usage string, field func(*Config) *[]string) setting {
+	return setting{env: env, flag: flag, usage: usage, set: func(c *Config, raw string) error {
+		var out []string
+		for _, part := range strings.Split(raw, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+		*field(c) = out
+		return nil
+	}}
+}
@@ -0,0 +1,212 @@
+package events
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	DefaultRetention = time.Hour
+	DefaultMaxEvents = 5000
+)
+
+// Filter selects events by exact match; empty fields match everything.
+type Filter struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Type      string
+	Reason    string
+}
+
+func (f Filter) matches(e Event) bool {
+	switch {
+	case f.Namespace != "" && e.Namespace != f.Namespace:
+		return false
+	case f.Kind != "" && e.InvolvedObject.Kind != f.Kind:
+		return false
+	case f.Name != "" && e.InvolvedObject.Name != f.Name:
+		return false
+	case f.Type != "" && e.Type != f.Type:
+		return false
+	case f.Reason != "" && e.Reason != f.Reason:
+		return false
+	default:
+		return true
+	}
+}
+
+// Buffer keeps the events seen in the last retention period, up to max
+// events. When full, the events with the oldest LastSeen are dropped first.
+// It is safe for concurrent use.
+type Buffer struct {
+	mu        sync.RWMutex
+	retention time.Duration
+	max       int
+	events    map[string]Event
+
+	// byAge orders the events by LastSeen so pruning on the informer path
+	// does not sort the whole buffer. Entries are not removed when an event
+	// is updated or deleted; pruneLocked skips the ones that no longer match.
+	byAge ageHeap
+}
+
+func NewBuffer(retention time.Duration, max int) *Buffer {
+	return &Buffer{
+		retention: retention,
+		max:       max,
+		events:    make(map[string]Event),
+	}
+}
+
+func eventKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// Upsert adds or replaces an event. Events already outside the retention
+// window are ignored.
+func (b *Buffer) Upsert(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cutoff := utils.Now().Add(-b.retention)
+	if e.LastSeen.Before(cutoff) {
+		return
+	}
+
+	key := eventKey(e.Namespace, e.Name)
+	b.events[key] = e
+	heap.Push(&b.byAge, ageEntry{key: key, lastSeen: e.LastSeen})
+
+	b.pruneLocked(cutoff)
+}
+
+func (b *Buffer) Delete(namespace, name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.events, eventKey(namespace, name))
+}
+
+// pruneLocked drops expired events and then, if still over capacity, the
+// oldest ones. It only looks at the oldest entries, so it is cheap enough to
+// run on every Upsert.
+func (b *Buffer) pruneLocked(cutoff time.Time) {
+	for len(b.byAge) > 0 {
+		oldest := b.byAge[0]
+
+		e, ok := b.events[oldest.key]
+		if ok && !e.LastSeen.Equal(oldest.lastSeen) {
+			ok = false
+		}
+
+		if ok && !oldest.lastSeen.Before(cutoff) && len(b.events) <= b.max {
+			break
+		}
+
+		heap.Pop(&b.byAge)
+		if ok {
+			delete(b.events, oldest.key)
+		}
+	}
+
+	// Updates and deletes leave stale entries behind; rebuild once they
+	// outnumber the live ones.
+	if len(b.byAge) > 2*len(b.events)+64 {
+		b.byAge = b.byAge[:0]
+		for k, e := range b.events {
+			b.byAge = append(b.byAge, ageEntry{key: k, lastSeen: e.LastSeen})
+		}
+		heap.Init(&b.byAge)
+	}
+}
+
+// ageEntry records the LastSeen an event had when it was added to byAge.
+type ageEntry struct {
+	key      string
+	lastSeen time.Time
+}
+
+// ageHeap is a min-heap of events by LastSeen for container/heap.
+type ageHeap []ageEntry
+
+func (h ageHeap) Len() int { return len(h) }
+
+func (h ageHeap) Less(i, j int) bool {
+	if !h[i].lastSeen.Equal(h[j].lastSeen) {
+		return h[i].lastSeen.Before(h[j].lastSeen)
+	}
+	return h[i].key < h[j].key
+}
+
+func (h ageHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *ageHeap) Push(x any) { *h = append(*h, x.(ageEntry)) }
+
+func (h *ageHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// List returns the retained events matching f, most recent first.
+func (b *Buffer) List(f Filter) []Event {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	cutoff := utils.Now().Add(-b.retention)
+
+	out := make([]Event, 0)
+	for _, e := range b.events {
+		if !e.LastSeen.Before(cutoff) && f.matches(e) {
+			out = append(out, e)
+		}
+	}
+
+	sortNewestFirst(out)
+	return out
+}
+
+// ObjectKey is the key WarningsByObject uses for an involved object.
+// Cluster-scoped objects such as nodes have an empty namespace.
+func ObjectKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// WarningsByObject returns, for every object of kind with retained Warning
+// events, its most recent limit warnings keyed by ObjectKey. It is meant to
+// be called once per snapshot build.
+func (b *Buffer) WarningsByObject(kind string, limit int) map[string][]Warning {
+	events := b.List(Filter{Kind: kind, Type: v1.EventTypeWarning})
+
+	out := make(map[string][]Warning)
+	for _, e := range events {
+		key := ObjectKey(e.InvolvedObject.Namespace, e.InvolvedObject.Name)
+		if len(out[key]) >= limit {
+			continue
+		}
+		out[key] = append(out[key], Warning{
+			Reason:   e.Reason,
+			Message:  e.Message,
+			Count:    e.Count,
+			LastSeen: e.LastSeen,
+		})
+	}
+
+	return out
+}
+
+func sortNewestFirst(events []Event) {
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if !a.LastSeen.Equal(b.LastSeen) {
+			return a.LastSeen.After(b.LastSeen)
+		}
+		return eventKey(a.Namespace, a.Name) < eventKey(b.Namespace, b.Name)
+	})
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferRetentionAndCapacity(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := utils.Now
+	utils.Now = func() time.Time { return now }
+	defer func() { utils.Now = originalNow }()
+
+	buf := NewBuffer(time.Hour, 2)
+
+	buf.Upsert(Event{Namespace: "default", Name: "expired", LastSeen: now.Add(-2 * time.Hour)})
+	require.Empty(t, buf.List(Filter{}))
+
+	buf.Upsert(Event{Namespace: "default", Name: "a", LastSeen: now.Add(-30 * time.Minute)})
+	buf.Upsert(Event{Namespace: "default", Name: "b", LastSeen: now.Add(-10 * time.Minute)})
+	buf.Upsert(Event{Namespace: "default", Name: "c", LastSeen: now.Add(-20 * time.Minute)})
+	require.Equal(t, []string{"b", "c"}, eventNames(buf.List(Filter{})))
+
+	// Updating an event replaces it rather than adding a second entry.
+	buf.Upsert(Event{Namespace: "default", Name: "c", LastSeen: now, Count: 2})
+	require.Equal(t, []string{"c", "b"}, eventNames(buf.List(Filter{})))
+
+	// Events age out of the window even without new writes.
+	now = now.Add(55 * time.Minute)
+	require.Equal(t, []string{"c"}, eventNames(buf.List(Filter{})))
+
+	buf.Delete("default", "c")
+	require.Empty(t, buf.List(Filter{}))
+}
+
+func TestBufferFilterAndWarnings(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := utils.Now
+	utils.Now = func() time.Time { return now }
+	defer func() { utils.Now = originalNow }()
+
+	buf := NewBuffer(time.Hour, 100)
+	pod := ObjectRef{Kind: "Pod", Namespace: "default", Name: "api-0"}
+	node := ObjectRef{Kind: "Node", Name: "node-1"}
+
+	buf.Upsert(Event{Namespace: "default", Name: "e1", Type: "Warning", Reason: "BackOff", InvolvedObject: pod, LastSeen: now.Add(-3 * time.Minute)})
+	buf.Upsert(Event{Namespace: "default", Name: "e2", Type: "Warning", Reason: "Unhealthy", InvolvedObject: pod, LastSeen: now.Add(-1 * time.Minute)})
+	buf.Upsert(Event{Namespace: "default", Name: "e3", Type: "Normal", Reason: "Pulled", InvolvedObject: pod, LastSeen: now})
+	buf.Upsert(Event{Namespace: "default", Name: "e4", Type: "Warning", Reason: "NodeNotReady", InvolvedObject: node, LastSeen: now.Add(-2 * time.Minute)})
+
+	require.Equal(t, []string{"e2", "e4", "e1"}, eventNames(buf.List(Filter{Type: "Warning"})))
+	require.Equal(t, []string{"e3", "e2", "e1"}, eventNames(buf.List(Filter{Kind: "Pod", Name: "api-0"})))
+	require.Equal(t, []string{"e1"}, eventNames(buf.List(Filter{Reason: "BackOff"})))
+	require.Empty(t, buf.List(Filter{Namespace: "kube-system"}))
+
+	podWarnings := buf.WarningsByObject("Pod", 1)
+	require.Len(t, podWarnings, 1)
+	require.Equal(t, []Warning{{Reason: "Unhealthy", LastSeen: now.Add(-1 * time.Minute)}}, podWarnings[ObjectKey("default", "api-0")])
+
+	nodeWarnings := buf.WarningsByObject("Node", 5)
+	require.Len(t, nodeWarnings[ObjectKey("", "node-1")], 1)
+}
+
+func eventNames(events []Event) []string {
+	out := make([]string, 0, len(events))
+	for _, e := range events {
+		out = append(out, e.Name)
+	}
+	return out
+}
+
+func TestBufferCapacityWithUpdatesAndDeletes(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := utils.Now
+	utils.Now = func() time.Time { return now }
+	defer func() { utils.Now = originalNow }()
+
+	buf := NewBuffer(time.Hour, 3)
+
+	for i := range 200 {
+		name := string(rune('a' + i%5))
+		buf.Upsert(Event{Namespace: "default", Name: name, LastSeen: now.Add(time.Duration(i) * time.Second)})
+		if i%7 == 0 {
+			buf.Delete("default", name)
+		}
+	}
+
+	// The three most recently seen of the five names survive.
+	require.Equal(t, []string{"e", "d", "c"}, eventNames(buf.List(Filter{})))
+	require.LessOrEqual(t, len(buf.byAge), 2*len(buf.events)+64)
+}
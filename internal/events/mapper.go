@@ -0,0 +1,59 @@
+package events
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// FromKube maps a core/v1 Event. Events written through the events.k8s.io
+// API only set EventTime and Series, so the timestamps and count fall back
+// to those.
+func FromKube(e *v1.Event) Event {
+	count := e.Count
+	if e.Series != nil && e.Series.Count > count {
+		count = e.Series.Count
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	source := e.Source.Component
+	if source == "" {
+		source = e.ReportingController
+	}
+
+	firstSeen := firstNonZero(e.FirstTimestamp.Time, e.EventTime.Time, e.CreationTimestamp.Time)
+
+	lastSeen := e.LastTimestamp.Time
+	if e.Series != nil && e.Series.LastObservedTime.After(lastSeen) {
+		lastSeen = e.Series.LastObservedTime.Time
+	}
+	lastSeen = firstNonZero(lastSeen, firstSeen)
+
+	return Event{
+		Namespace: e.Namespace,
+		Name:      e.Name,
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Message:   e.Message,
+		Source:    source,
+		Count:     count,
+		InvolvedObject: ObjectRef{
+			Kind:      e.InvolvedObject.Kind,
+			Namespace: e.InvolvedObject.Namespace,
+			Name:      e.InvolvedObject.Name,
+		},
+		FirstSeen: firstSeen.UTC(),
+		LastSeen:  lastSeen.UTC(),
+	}
+}
+
+func firstNonZero(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
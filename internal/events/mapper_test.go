@@ -0,0 +1,18 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/testutil"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestFromKube(t *testing.T) {
+	testutil.RunGoldenTest(
+		t,
+		"testdata/fromKube",
+		func(input v1.Event) Event {
+			return FromKube(&input)
+		},
+	)
+}
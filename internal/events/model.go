@@ -0,0 +1,34 @@
+package events
+
+import "time"
+
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type Event struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	Type    string `json:"type"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+	Source  string `json:"source"`
+	Count   int32  `json:"count"`
+
+	InvolvedObject ObjectRef `json:"involvedObject"`
+
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// Warning is the short form of a Warning event attached to the pod or node
+// it was reported for.
+type Warning struct {
+	Reason   string    `json:"reason"`
+	Message  string    `json:"message"`
+	Count    int32     `json:"count"`
+	LastSeen time.Time `json:"lastSeen"`
+}
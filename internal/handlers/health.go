@@ -3,14 +3,48 @@ package handlers
 import (
 	"log/slog"
 	"net/http"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
 )
 
-func ReadyHandler() http.HandlerFunc {
+// ReadinessCheck is one condition /readyz depends on. Check returns nil when
+// the condition holds, or an error describing why the service is not ready.
+type ReadinessCheck struct {
+	Name  string
+	Check func() error
+}
+
+type readinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// ReadyHandler reports 200 when every check passes and 503 otherwise. The
+// JSON body lists the result of each check so a failing probe explains
+// itself.
+func ReadyHandler(checks ...ReadinessCheck) http.HandlerFunc {
 	return func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte("ready")); err != nil {
-			slog.Error("failed to write ready response", "error", err)
+		resp := readinessResponse{
+			Status: "ready",
+			Checks: make(map[string]string, len(checks)),
 		}
+		status := http.StatusOK
+
+		for _, c := range checks {
+			if err := c.Check(); err != nil {
+				resp.Checks[c.Name] = err.Error()
+				resp.Status = "not ready"
+				status = http.StatusServiceUnavailable
+				continue
+			}
+			resp.Checks[c.Name] = "ok"
+		}
+
+		if status != http.StatusOK {
+			slog.Debug("not ready", "checks", resp.Checks)
+		}
+
+		utils.WriteJSON(w, status, resp)
 	}
 }
 
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadyHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []ReadinessCheck
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "no checks",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"ready","checks":{}}`,
+		},
+		{
+			name: "all checks pass",
+			checks: []ReadinessCheck{
+				{Name: "informers", Check: func() error { return nil }},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"ready","checks":{"informers":"ok"}}`,
+		},
+		{
+			name: "failing check",
+			checks: []ReadinessCheck{
+				{Name: "informers", Check: func() error { return nil }},
+				{Name: "nodes", Check: func() error { return errors.New("no snapshot yet") }},
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   `{"status":"not ready","checks":{"informers":"ok","nodes":"no snapshot yet"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ReadyHandler(tt.checks...)(rec, httptest.NewRequest("GET", "/readyz", nil))
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			require.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package history
+
+import (
+	"sync"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	DefaultRetention  = time.Hour
+	DefaultResolution = time.Minute
+)
+
+// History keeps a bounded series of samples per node and per pod. Samples
+// are bucketed by resolution, keeping the latest value in each bucket, and
+// buckets older than retention are dropped, so a series never holds more
+// than retention/resolution+1 samples. Series of objects that have not been
+// seen for a whole retention period are removed. It is safe for concurrent
+// use.
+type History struct {
+	mu         sync.RWMutex
+	retention  time.Duration
+	resolution time.Duration
+	nodes      map[string]*series[NodeSample]
+	pods       map[string]*series[PodSample]
+}
+
+func New(retention, resolution time.Duration) *History {
+	return &History{
+		retention:  retention,
+		resolution: resolution,
+		nodes:      make(map[string]*series[NodeSample]),
+		pods:       make(map[string]*series[PodSample]),
+	}
+}
+
+func (h *History) Retention() time.Duration {
+	return h.retention
+}
+
+func (h *History) Resolution() time.Duration {
+	return h.resolution
+}
+
+// RecordNodes adds a sample for each node in a snapshot taken at now and
+// returns the samples keyed by node name.
+func (h *History) RecordNodes(list []nodes.Node, now time.Time) map[string]NodeSample {
+	samples := make(map[string]NodeSample, len(list))
+	for _, n := range list {
+		samples[n.Name] = NodeSample{
+			Timestamp:     now,
+			Ready:         n.Ready,
+			CPUMillicores: milliValue(n.CPU.Used),
+			MemoryBytes:   value(n.Memory.Used),
+		}
+	}
+
+	h.AddNodeSamples(samples, now)
+	return samples
+}
+
+// RecordPods adds a sample for each pod in a snapshot taken at now and
+// returns the samples keyed by PodKey.
+func (h *History) RecordPods(list []pods.Pod, now time.Time) map[string]PodSample {
+	samples := make(map[string]PodSample, len(list))
+	for _, p := range list {
+		sample := PodSample{
+			Timestamp: now,
+			Phase:     p.Phase,
+			Ready:     p.Ready,
+			Restarts:  p.Restarts,
+		}
+		if p.Usage != nil {
+			sample.CPUMillicores = milliValue(p.Usage.CPU)
+			sample.MemoryBytes = value(p.Usage.Memory)
+		}
+		samples[PodKey(p.Namespace, p.Name)] = sample
+	}
+
+	h.AddPodSamples(samples, now)
+	return samples
+}
+
+// AddNodeSamples adds samples keyed by node name, as returned by
+// RecordNodes, and forgets nodes without a sample since now minus the
+// retention.
+func (h *History) AddNodeSamples(samples map[string]NodeSample, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for name, v := range samples {
+		record(h, h.nodes, name, v)
+	}
+	prune(h.nodes, now.Add(-h.retention))
+}
+
+// AddPodSamples adds samples keyed by PodKey the same way AddNodeSamples
+// does.
+func (h *History) AddPodSamples(samples map[string]PodSample, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for key, v := range samples {
+		record(h, h.pods, key, v)
+	}
+	prune(h.pods, now.Add(-h.retention))
+}
+
+// Dump is a copy of every series, oldest sample first.
+type Dump struct {
+	Nodes map[string][]NodeSample `json:"nodes"`
+	Pods  map[string][]PodSample  `json:"pods"`
+}
+
+// Dump copies every series so it can be saved and later passed to Restore.
+func (h *History) Dump() Dump {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return Dump{
+		Nodes: dumpSeries(h.nodes),
+		Pods:  dumpSeries(h.pods),
+	}
+}
+
+// Restore adds the samples in d. Samples are bucketed with the current
+// resolution, and series older than the retention at now are dropped.
+func (h *History) Restore(d Dump, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	restoreSeries(h, h.nodes, d.Nodes)
+	restoreSeries(h, h.pods, d.Pods)
+	prune(h.nodes, now.Add(-h.retention))
+	prune(h.pods, now.Add(-h.retention))
+}
+
+// Node returns the samples of a node between from and to, keeping the
+// latest sample in each step. ok is false when there is no history for the
+// node.
+func (h *History) Node(name string, from, to time.Time, step time.Duration) ([]NodeSample, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	s, ok := h.nodes[name]
+	if !ok {
+		return nil, false
+	}
+	return s.query(from, to, step), true
+}
+
+// Pod returns the samples of a pod the same way Node does.
+func (h *History) Pod(namespace, name string, from, to time.Time, step time.Duration) ([]PodSample, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	s, ok := h.pods[PodKey(namespace, name)]
+	if !ok {
+		return nil, false
+	}
+	return s.query(from, to, step), true
+}
+
+func PodKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+type sample interface {
+	at() time.Time
+}
+
+func record[T sample](h *History, all map[string]*series[T], key string, v T) {
+	s, ok := all[key]
+	if !ok {
+		s = newSeries[T](int(h.retention/h.resolution) + 1)
+		all[key] = s
+	}
+	s.add(v, h.resolution)
+}
+
+func dumpSeries[T sample](all map[string]*series[T]) map[string][]T {
+	out := make(map[string][]T, len(all))
+	for key, s := range all {
+		samples := make([]T, 0, s.n)
+		for i := 0; i < s.n; i++ {
+			samples = append(samples, s.get(i))
+		}
+		out[key] = samples
+	}
+	return out
+}
+
+func restoreSeries[T sample](h *History, all map[string]*series[T], dump map[string][]T) {
+	for key, samples := range dump {
+		for _, v := range samples {
+			record(h, all, key, v)
+		}
+	}
+}
+
+func prune[T sample](all map[string]*series[T], cutoff time.Time) {
+	for key, s := range all {
+		if last, ok := s.last(); !ok || last.at().Before(cutoff) {
+			delete(all, key)
+		}
+	}
+}
+
+// series is a ring buffer of samples in time order.
+type series[T sample] struct {
+	buf   []T
+	start int
+	n     int
+}
+
+func newSeries[T sample](capacity int) *series[T] {
+	return &series[T]{buf: make([]T, capacity)}
+}
+
+func (s *series[T]) get(i int) T {
+	return s.buf[(s.start+i)%len(s.buf)]
+}
+
+func (s *series[T]) last() (T, bool) {
+	if s.n == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.get(s.n - 1), true
+}
+
+// add appends v, or replaces the last sample when both fall in the same
+// resolution bucket. When full, the oldest sample is overwritten.
+func (s *series[T]) add(v T, resolution time.Duration) {
+	if last, ok := s.last(); ok {
+		bucket := v.at().Truncate(resolution)
+		if bucket.Equal(last.at().Truncate(resolution)) {
+			s.buf[(s.start+s.n-1)%len(s.buf)] = v
+			return
+		}
+		if bucket.Before(last.at()) {
+			return
+		}
+	}
+
+	if s.n < len(s.buf) {
+		s.buf[(s.start+s.n)%len(s.buf)] = v
+		s.n++
+		return
+	}
+
+	s.buf[s.start] = v
+	s.start = (s.start + 1) % len(s.buf)
+}
+
+// query returns the samples in [from, to], keeping the latest sample in each
+// step-sized bucket.
+func (s *series[T]) query(from, to time.Time, step time.Duration) []T {
+	out := []T{}
+	var bucket time.Time
+
+	for i := 0; i < s.n; i++ {
+		v := s.get(i)
+		t := v.at()
+		if t.Before(from) || t.After(to) {
+			continue
+		}
+
+		b := t.Truncate(step)
+		if len(out) > 0 && b.Equal(bucket) {
+			out[len(out)-1] = v
+			continue
+		}
+
+		bucket = b
+		out = append(out, v)
+	}
+
+	return out
+}
+
+// milliValue and value parse snapshot quantity strings such as "250m" or
+// "512Mi"; empty or unparsable values are nil.
+func milliValue(raw string) *int64 {
+	q, ok := parseQuantity(raw)
+	if !ok {
+		return nil
+	}
+	v := q.MilliValue()
+	return &v
+}
+
+func value(raw string) *int64 {
+	q, ok := parseQuantity(raw)
+	if !ok {
+		return nil
+	}
+	v := q.Value()
+	return &v
+}
+
+func parseQuantity(raw string) (resource.Quantity, bool) {
+	if raw == "" {
+		return resource.Quantity{}, false
+	}
+
+	q, err := resource.ParseQuantity(raw)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+	return q, true
+}
@@ -0,0 +1,101 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryBucketsAndRetention(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := New(5*time.Minute, time.Minute)
+
+	// Two snapshots in the same minute keep only the latest.
+	h.RecordNodes([]nodes.Node{{Name: "node-1", CPU: nodes.Usage{Used: "100m"}}}, start)
+	h.RecordNodes([]nodes.Node{{Name: "node-1", Ready: true, CPU: nodes.Usage{Used: "200m"}}}, start.Add(30*time.Second))
+
+	samples, ok := h.Node("node-1", start, start.Add(time.Hour), time.Minute)
+	require.True(t, ok)
+	require.Len(t, samples, 1)
+	require.True(t, samples[0].Ready)
+	require.Equal(t, int64(200), *samples[0].CPUMillicores)
+	require.Nil(t, samples[0].MemoryBytes)
+
+	// The ring holds retention/resolution+1 buckets, dropping the oldest.
+	for i := 1; i <= 10; i++ {
+		h.RecordNodes([]nodes.Node{{Name: "node-1", Memory: nodes.Usage{Used: "1Gi"}}}, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	samples, _ = h.Node("node-1", start, start.Add(time.Hour), time.Minute)
+	require.Len(t, samples, 6)
+	require.Equal(t, start.Add(5*time.Minute), samples[0].Timestamp)
+	require.Equal(t, int64(1<<30), *samples[0].MemoryBytes)
+
+	// Nodes not seen for a whole retention period are forgotten.
+	h.RecordNodes(nil, start.Add(20*time.Minute))
+	_, ok = h.Node("node-1", start, start.Add(time.Hour), time.Minute)
+	require.False(t, ok)
+}
+
+func TestHistoryPodQuery(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := New(time.Hour, time.Minute)
+
+	for i := range 10 {
+		h.RecordPods([]pods.Pod{{
+			Namespace: "default",
+			Name:      "api-0",
+			Phase:     "Running",
+			Restarts:  int32(i),
+			Usage:     &pods.ResourceUsage{CPU: "50m", Memory: "64Mi"},
+		}}, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	// A 5 minute step keeps the latest sample in each step.
+	samples, ok := h.Pod("default", "api-0", start.Add(2*time.Minute), start.Add(8*time.Minute), 5*time.Minute)
+	require.True(t, ok)
+	require.Len(t, samples, 2)
+	require.Equal(t, int32(4), samples[0].Restarts)
+	require.Equal(t, int32(8), samples[1].Restarts)
+	require.Equal(t, int64(50), *samples[1].CPUMillicores)
+	require.Equal(t, int64(64<<20), *samples[1].MemoryBytes)
+
+	samples, ok = h.Pod("default", "api-0", start.Add(2*time.Hour), start.Add(3*time.Hour), time.Minute)
+	require.True(t, ok)
+	require.Empty(t, samples)
+
+	_, ok = h.Pod("default", "api-1", start, start.Add(time.Hour), time.Minute)
+	require.False(t, ok)
+}
+
+func TestHistoryDumpRestore(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := New(time.Hour, time.Minute)
+
+	for i := range 3 {
+		at := start.Add(time.Duration(i) * time.Minute)
+		h.RecordNodes([]nodes.Node{{Name: "node-1", Ready: true}}, at)
+		h.RecordPods([]pods.Pod{{Namespace: "default", Name: "api-0", Restarts: int32(i)}}, at)
+	}
+
+	// A coarser resolution merges the restored samples into fewer buckets.
+	restored := New(time.Hour, 2*time.Minute)
+	restored.Restore(h.Dump(), start.Add(3*time.Minute))
+
+	nodeSamples, ok := restored.Node("node-1", start, start.Add(time.Hour), 2*time.Minute)
+	require.True(t, ok)
+	require.Len(t, nodeSamples, 2)
+
+	podSamples, ok := restored.Pod("default", "api-0", start, start.Add(time.Hour), 2*time.Minute)
+	require.True(t, ok)
+	require.Equal(t, []int32{1, 2}, []int32{podSamples[0].Restarts, podSamples[1].Restarts})
+
+	// Nothing survives a restore past the retention.
+	expired := New(time.Hour, time.Minute)
+	expired.Restore(h.Dump(), start.Add(2*time.Hour))
+	_, ok = expired.Node("node-1", start, start.Add(3*time.Hour), time.Minute)
+	require.False(t, ok)
+}
@@ -0,0 +1,27 @@
+package history
+
+import "time"
+
+// NodeSample is a node's state at one point in time. CPU and memory are nil
+// when metrics.k8s.io had no sample for the node.
+type NodeSample struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Ready         bool      `json:"ready"`
+	CPUMillicores *int64    `json:"cpuMillicores"`
+	MemoryBytes   *int64    `json:"memoryBytes"`
+}
+
+// PodSample is a pod's state at one point in time. CPU and memory are nil
+// when metrics.k8s.io had no sample for the pod.
+type PodSample struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Phase         string    `json:"phase"`
+	Ready         bool      `json:"ready"`
+	Restarts      int32     `json:"restarts"`
+	CPUMillicores *int64    `json:"cpuMillicores"`
+	MemoryBytes   *int64    `json:"memoryBytes"`
+}
+
+func (s NodeSample) at() time.Time { return s.Timestamp }
+
+func (s PodSample) at() time.Time { return s.Timestamp }
@@ -3,13 +3,18 @@ package informers
 import (
 	"context"
 	"log/slog"
+	"sync/atomic"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 )
 
 type Manager struct {
 	factory informers.SharedInformerFactory
+	synced  atomic.Bool
 }
 
 func NewManager(client kubernetes.Interface) *Manager {
@@ -24,11 +29,94 @@ func (m *Manager) Factory() informers.SharedInformerFactory {
 	return m.factory
 }
 
-func (m *Manager) Start(ctx context.Context) {
+// OnChange calls notify for every add, update and delete seen by informer.
+// Updates that leave the resource version unchanged are ignored. Handlers
+// must be registered before Start.
+func (m *Manager) OnChange(informer cache.SharedIndexInformer, notify func()) error {
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(any) {
+			notify()
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			if sameResourceVersion(oldObj, newObj) {
+				return
+			}
+			notify()
+		},
+		DeleteFunc: func(any) {
+			notify()
+		},
+	})
+
+	return err
+}
+
+// OnEvent registers the core/v1 Events informer and calls upsert for every
+// added or updated event and remove for every deleted one. It must be
+// called before Start.
+func (m *Manager) OnEvent(upsert, remove func(*corev1.Event)) error {
+	_, err := m.factory.Core().V1().Events().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if e, ok := obj.(*corev1.Event); ok {
+				upsert(e)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			if sameResourceVersion(oldObj, newObj) {
+				return
+			}
+			if e, ok := newObj.(*corev1.Event); ok {
+				upsert(e)
+			}
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if e, ok := obj.(*corev1.Event); ok {
+				remove(e)
+			}
+		},
+	})
+
+	return err
+}
+
+// Start runs the informers and blocks until their caches have synced. It
+// returns false if ctx is cancelled first.
+func (m *Manager) Start(ctx context.Context) bool {
 	slog.Info("starting informers")
 
 	m.factory.Start(ctx.Done())
-	m.factory.WaitForCacheSync(ctx.Done())
+	for informerType, ok := range m.factory.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			slog.Warn("informer cache did not sync", "type", informerType.String())
+			return false
+		}
+	}
 
+	m.synced.Store(true)
 	slog.Info("informers synced")
+
+	return true
+}
+
+// HasSynced reports whether every informer cache has completed its initial
+// list.
+func (m *Manager) HasSynced() bool {
+	return m.synced.Load()
+}
+
+func sameResourceVersion(oldObj, newObj any) bool {
+	oldMeta, ok := oldObj.(metav1.Object)
+	if !ok {
+		return false
+	}
+
+	newMeta, ok := newObj.(metav1.Object)
+	if !ok {
+		return false
+	}
+
+	return oldMeta.GetResourceVersion() == newMeta.GetResourceVersion()
 }
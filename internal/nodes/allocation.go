@@ -0,0 +1,152 @@
+package nodes
+
+import (
+	"fmt"
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// buildAllocation sums the requests and limits of the non-terminated pods
+// scheduled on n and compares them with its allocatable capacity. Limits
+// are summed over the containers that set one, as kubectl describe node
+// does, so a pod without limits adds nothing to them.
+func buildAllocation(n *v1.Node, pods []*v1.Pod) Allocation {
+	requests := v1.ResourceList{}
+	limits := v1.ResourceList{}
+	count := 0
+
+	for _, p := range pods {
+		if p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed {
+			continue
+		}
+		count++
+
+		reqs, lims := PodResources(p)
+		addResources(requests, reqs)
+		addResources(limits, lims)
+	}
+
+	alloc := n.Status.Allocatable
+	podsAlloc := alloc[v1.ResourcePods]
+
+	return Allocation{
+		CPU:              resourceAllocation(requests, limits, alloc, v1.ResourceCPU, formatMilli),
+		Memory:           resourceAllocation(requests, limits, alloc, v1.ResourceMemory, formatMebi),
+		EphemeralStorage: resourceAllocation(requests, limits, alloc, v1.ResourceEphemeralStorage, formatMebi),
+		Pods: PodAllocation{
+			Count:       count,
+			Allocatable: podsAlloc.Value(),
+			Percent:     percent(float64(count), float64(podsAlloc.Value())),
+		},
+	}
+}
+
+// PodResources returns the effective requests and limits of p the way the
+// scheduler accounts for them: pod-level resources when set, otherwise the
+// larger of the app containers plus sidecars and the largest regular init
+// container (with the sidecars started before it), plus the pod overhead.
+func PodResources(p *v1.Pod) (v1.ResourceList, v1.ResourceList) {
+	requests := containerResources(p, func(r v1.ResourceRequirements) v1.ResourceList { return r.Requests })
+	limits := containerResources(p, func(r v1.ResourceRequirements) v1.ResourceList { return r.Limits })
+
+	if r := p.Spec.Resources; r != nil {
+		for name, q := range r.Requests {
+			requests[name] = q.DeepCopy()
+		}
+		for name, q := range r.Limits {
+			limits[name] = q.DeepCopy()
+		}
+	}
+
+	addResources(requests, p.Spec.Overhead)
+	if len(limits) > 0 {
+		addResources(limits, p.Spec.Overhead)
+	}
+
+	return requests, limits
+}
+
+func containerResources(p *v1.Pod, pick func(v1.ResourceRequirements) v1.ResourceList) v1.ResourceList {
+	out := v1.ResourceList{}
+	for _, c := range p.Spec.Containers {
+		addResources(out, pick(c.Resources))
+	}
+
+	sidecars := v1.ResourceList{}
+	initMax := v1.ResourceList{}
+	for _, c := range p.Spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			addResources(sidecars, pick(c.Resources))
+			continue
+		}
+
+		// A regular init container runs alongside the sidecars started
+		// before it.
+		running := v1.ResourceList{}
+		addResources(running, sidecars)
+		addResources(running, pick(c.Resources))
+		maxResources(initMax, running)
+	}
+
+	addResources(out, sidecars)
+	maxResources(out, initMax)
+
+	return out
+}
+
+func addResources(dst, src v1.ResourceList) {
+	for name, q := range src {
+		total := dst[name]
+		total.Add(q)
+		dst[name] = total
+	}
+}
+
+func maxResources(dst, src v1.ResourceList) {
+	for name, q := range src {
+		if current, ok := dst[name]; !ok || q.Cmp(current) > 0 {
+			dst[name] = q.DeepCopy()
+		}
+	}
+}
+
+func resourceAllocation(
+	requests, limits, allocatable v1.ResourceList,
+	name v1.ResourceName,
+	format func(resource.Quantity) string,
+) ResourceAllocation {
+	req := requests[name]
+	lim := limits[name]
+	alloc := allocatable[name]
+
+	total := float64(alloc.MilliValue())
+
+	return ResourceAllocation{
+		Requests:        format(req),
+		Limits:          format(lim),
+		Allocatable:     format(alloc),
+		RequestsPercent: percent(float64(req.MilliValue()), total),
+		LimitsPercent:   percent(float64(lim.MilliValue()), total),
+	}
+}
+
+// percent returns used as a percentage of total rounded to one decimal, or
+// nil when total is zero.
+func percent(used, total float64) *float64 {
+	if total <= 0 {
+		return nil
+	}
+
+	v := math.Round(used/total*1000) / 10
+	return &v
+}
+
+func formatMilli(q resource.Quantity) string {
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+func formatMebi(q resource.Quantity) string {
+	return fmt.Sprintf("%dMi", q.Value()/(1024*1024))
+}
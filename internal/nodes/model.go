@@ -1,5 +1,11 @@
 package nodes
 
+import (
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
+)
+
 type Usage struct {
 	Used  string `json:"used"`
 	Total string `json:"total"`
@@ -17,24 +23,81 @@ type Node struct {
 	Ready bool   `json:"ready"`
 	Age   string `json:"age"`
 
+	CreatedAt time.Time `json:"createdAt"`
+
 	Labels map[string]string `json:"labels"`
 	Taints []string          `json:"taints"`
 
+	// MetricsAvailable is false when metrics.k8s.io had no sample for the
+	// node; CPU and memory Used are then empty.
+	MetricsAvailable bool       `json:"metricsAvailable"`
+	MetricsTimestamp *time.Time `json:"metricsTimestamp,omitempty"`
+
 	CPU    Usage `json:"cpu"`
 	Memory Usage `json:"memory"`
 
+	// Allocation is the capacity committed to the pods on the node; unlike
+	// CPU and Memory Used it does not depend on metrics.k8s.io.
+	Allocation Allocation `json:"allocation"`
+
 	Conditions []Condition `json:"conditions"`
 
+	// Warnings are the most recent Warning events reported for the node.
+	Warnings []events.Warning `json:"warnings,omitempty"`
+
 	Workloads NodeWorkloads `json:"workloads"`
 }
 
+// Allocation compares the summed requests and limits of the non-terminated
+// pods on a node with its allocatable capacity.
+type Allocation struct {
+	CPU              ResourceAllocation `json:"cpu"`
+	Memory           ResourceAllocation `json:"memory"`
+	EphemeralStorage ResourceAllocation `json:"ephemeralStorage"`
+	Pods             PodAllocation      `json:"pods"`
+}
+
+// ResourceAllocation holds the committed amount of one resource. The
+// percentages are relative to allocatable and are nil when the node reports
+// none; a LimitsPercent above 100 means the node is overcommitted.
+type ResourceAllocation struct {
+	Requests        string   `json:"requests"`
+	Limits          string   `json:"limits"`
+	Allocatable     string   `json:"allocatable"`
+	RequestsPercent *float64 `json:"requestsPercent"`
+	LimitsPercent   *float64 `json:"limitsPercent"`
+}
+
+type PodAllocation struct {
+	Count       int      `json:"count"`
+	Allocatable int64    `json:"allocatable"`
+	Percent     *float64 `json:"percent"`
+}
+
+// NodeWorkloads groups the pods scheduled on a node by the workload that
+// owns them. Deployments also holds other ReplicaSet owners such as Argo
+// Rollouts, and Jobs holds Jobs and the CronJobs that created them; Kind
+// tells them apart.
 type NodeWorkloads struct {
 	Deployments  []Workload `json:"deployments"`
 	StatefulSets []Workload `json:"statefulSets"`
-	System       []string   `json:"system"`
+	DaemonSets   []Workload `json:"daemonSets"`
+	Jobs         []Workload `json:"jobs"`
+
+	// Other holds ReplicaSets without an owner and pods controlled by
+	// anything else, such as custom operators.
+	Other []Workload `json:"other"`
+
+	// BarePods are pods without a controller, as "namespace/name".
+	BarePods []string `json:"barePods"`
+
+	// System lists the pods in kube-system by name. They are also counted
+	// under their owner above.
+	System []string `json:"system"`
 }
 
 type Workload struct {
+	Kind      string `json:"kind"`
 	Namespace string `json:"namespace"`
 	Name      string `json:"name"`
 	Pods      int    `json:"pods"`
@@ -3,12 +3,18 @@ package nodes
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sort"
+	"time"
 
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
 	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
@@ -16,24 +22,45 @@ type Service interface {
 	BuildSnapshot(ctx context.Context) ([]Node, error)
 }
 
+const (
+	maxWarningsPerNode = 5
+)
+
 type NodeService struct {
-	nodeLister    corev1listers.NodeLister
-	podLister     corev1listers.PodLister
-	metricsClient *metricsclient.Clientset
+	nodeLister     corev1listers.NodeLister
+	podLister      corev1listers.PodLister
+	metricsClient  metricsclient.Interface
+	metricsBackoff *utils.Backoff
+	metricsCache   *utils.Cache[map[string]*metricsv1beta1.NodeMetrics]
+	resolver       *workloads.OwnerResolver
+	events         *events.Buffer
 }
 
+// NewNodeService builds a NodeService. A nil metricsClient disables usage
+// collection; nodes are then reported with metricsAvailable=false.
 func NewNodeService(
 	nodeLister corev1listers.NodeLister,
 	podLister corev1listers.PodLister,
-	metricsClient *metricsclient.Clientset,
+	metricsClient metricsclient.Interface,
+	resolver *workloads.OwnerResolver,
 ) *NodeService {
 	return &NodeService{
-		nodeLister:    nodeLister,
-		podLister:     podLister,
-		metricsClient: metricsClient,
+		nodeLister:     nodeLister,
+		podLister:      podLister,
+		metricsClient:  metricsClient,
+		resolver:       resolver,
+		metricsBackoff: utils.NewBackoff(),
+		metricsCache:   utils.NewCache[map[string]*metricsv1beta1.NodeMetrics](),
 	}
 }
 
+// WithEvents attaches the most recent Warning events from buf to each node
+// in the snapshot.
+func (s *NodeService) WithEvents(buf *events.Buffer) *NodeService {
+	s.events = buf
+	return s
+}
+
 func (s *NodeService) BuildSnapshot(ctx context.Context) ([]Node, error) {
 
 	nodesList, err := s.nodeLister.List(labels.Everything())
@@ -41,17 +68,7 @@ func (s *NodeService) BuildSnapshot(ctx context.Context) ([]Node, error) {
 		return nil, fmt.Errorf("failed to list nodes: %w", err)
 	}
 
-	metricsList, err := s.metricsClient.MetricsV1beta1().
-		NodeMetricses().
-		List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get node metrics: %w", err)
-	}
-
-	metricsByNode := make(map[string]v1.ResourceList)
-	for _, m := range metricsList.Items {
-		metricsByNode[m.Name] = m.Usage
-	}
+	metricsByNode := s.nodeMetrics(ctx)
 
 	allPods, err := s.podLister.List(labels.Everything())
 	if err != nil {
@@ -68,27 +85,67 @@ func (s *NodeService) BuildSnapshot(ctx context.Context) ([]Node, error) {
 		}
 	}
 
+	var warnings map[string][]events.Warning
+	if s.events != nil {
+		warnings = s.events.WarningsByObject("Node", maxWarningsPerNode)
+	}
+
 	out := make([]Node, 0, len(nodesList))
 
 	for _, n := range nodesList {
 
-		workloads := buildWorkloadsForNode(
-			podsByNode[n.Name],
-		)
-
-		out = append(out, mapNode(
+		node := mapNode(
 			n,
 			metricsByNode[n.Name],
-			workloads,
-		))
+			buildWorkloadsForNode(podsByNode[n.Name], s.resolver),
+		)
+		node.Allocation = buildAllocation(n, podsByNode[n.Name])
+		node.Warnings = warnings[events.ObjectKey("", n.Name)]
+
+		out = append(out, node)
 	}
 
 	return out, nil
 }
 
+// nodeMetrics lists node usage from metrics.k8s.io. A successful list is
+// reused until it is older than the cache TTL, so rebuilds don't list the
+// whole cluster each time. Failures are logged and back off, and the
+// snapshot is built without usage in the meantime, so clusters without
+// metrics-server still get node snapshots.
+func (s *NodeService) nodeMetrics(ctx context.Context) map[string]*metricsv1beta1.NodeMetrics {
+	if s.metricsClient == nil {
+		return nil
+	}
+	if cached, ok := s.metricsCache.Get(); ok {
+		return cached
+	}
+	if !s.metricsBackoff.Ready() {
+		return nil
+	}
+
+	list, err := s.metricsClient.MetricsV1beta1().
+		NodeMetricses().
+		List(ctx, metav1.ListOptions{})
+	if err != nil {
+		wait := s.metricsBackoff.Failure()
+		slog.Warn("node metrics unavailable", "error", err, "retryIn", wait)
+		return nil
+	}
+	s.metricsBackoff.Success()
+
+	out := make(map[string]*metricsv1beta1.NodeMetrics, len(list.Items))
+	for i := range list.Items {
+		out[list.Items[i].Name] = &list.Items[i]
+	}
+	s.metricsCache.Set(out)
+
+	return out
+}
+
 func mapNode(
 	n *v1.Node,
-	usage v1.ResourceList,
+	metrics *metricsv1beta1.NodeMetrics,
 	workloads NodeWorkloads,
 ) Node {
 
@@ -110,23 +167,41 @@ func mapNode(
 	cpuAlloc := n.Status.Allocatable[v1.ResourceCPU]
 	memAlloc := n.Status.Allocatable[v1.ResourceMemory]
 
-	cpuUsed := usage[v1.ResourceCPU]
-	memUsed := usage[v1.ResourceMemory]
+	var (
+		cpuUsed, memUsed string
+		sampledAt        *time.Time
+	)
+	if metrics != nil {
+		cpu := metrics.Usage[v1.ResourceCPU]
+		mem := metrics.Usage[v1.ResourceMemory]
+		cpuUsed = fmt.Sprintf("%dm", cpu.MilliValue())
+		memUsed = fmt.Sprintf("%dMi", mem.Value()/(1024*1024))
+
+		if !metrics.Timestamp.IsZero() {
+			ts := metrics.Timestamp.UTC()
+			sampledAt = &ts
+		}
+	}
 
 	return Node{
 		Name:  n.Name,
 		Ready: ready,
 		Age:   utils.AgeSince(n.CreationTimestamp.Time),
 
+		CreatedAt: n.CreationTimestamp.UTC(),
+
 		Labels: n.Labels,
 		Taints: formatTaints(n.Spec.Taints),
 
+		MetricsAvailable: metrics != nil,
+		MetricsTimestamp: sampledAt,
+
 		CPU: Usage{
-			Used:  fmt.Sprintf("%dm", cpuUsed.MilliValue()),
+			Used:  cpuUsed,
 			Total: fmt.Sprintf("%dm", cpuAlloc.MilliValue()),
 		},
 		Memory: Usage{
-			Used:  fmt.Sprintf("%dMi", memUsed.Value()/(1024*1024)),
+			Used:  memUsed,
 			Total: fmt.Sprintf("%dMi", memAlloc.Value()/(1024*1024)),
 		},
 
@@ -137,54 +212,80 @@ func mapNode(
 
 func buildWorkloadsForNode(
 	pods []*v1.Pod,
+	resolver *workloads.OwnerResolver,
 ) NodeWorkloads {
 
-	deployments := map[string]Workload{}
-	statefulSets := map[string]Workload{}
-	system := map[string]struct{}{}
+	byOwner := map[workloads.Owner]int{}
+	barePods := []string{}
+	system := []string{}
 
 	for _, pod := range pods {
 
 		if pod.Namespace == "kube-system" {
-			system[pod.Name] = struct{}{}
-			continue
+			system = append(system, pod.Name)
 		}
 
-		for _, owner := range pod.OwnerReferences {
-
-			switch owner.Kind {
-
-			case "ReplicaSet":
-				deployName := owner.Name
-				if i := utils.LastIndex(owner.Name, "-"); i > 0 {
-					deployName = owner.Name[:i]
-				}
+		owner := resolver.Resolve(pod)
+		if owner.Kind == workloads.KindPod {
+			barePods = append(barePods, pod.Namespace+"/"+pod.Name)
+			continue
+		}
 
-				key := pod.Namespace + "/" + deployName
+		byOwner[owner]++
+	}
 
-				w := deployments[key]
-				w.Namespace = pod.Namespace
-				w.Name = deployName
-				w.Pods++
-				deployments[key] = w
+	out := NodeWorkloads{
+		Deployments:  []Workload{},
+		StatefulSets: []Workload{},
+		DaemonSets:   []Workload{},
+		Jobs:         []Workload{},
+		Other:        []Workload{},
+		BarePods:     barePods,
+		System:       system,
+	}
 
-			case "StatefulSet":
-				key := pod.Namespace + "/" + owner.Name
+	for owner, count := range byOwner {
+		w := Workload{
+			Kind:      owner.Kind,
+			Namespace: owner.Namespace,
+			Name:      owner.Name,
+			Pods:      count,
+		}
 
-				w := statefulSets[key]
-				w.Namespace = pod.Namespace
-				w.Name = owner.Name
-				w.Pods++
-				statefulSets[key] = w
-			}
+		switch owner.Kind {
+		case workloads.KindDeployment, workloads.KindRollout:
+			out.Deployments = append(out.Deployments, w)
+		case workloads.KindStatefulSet:
+			out.StatefulSets = append(out.StatefulSets, w)
+		case workloads.KindDaemonSet:
+			out.DaemonSets = append(out.DaemonSets, w)
+		case workloads.KindJob, workloads.KindCronJob:
+			out.Jobs = append(out.Jobs, w)
+		default:
+			out.Other = append(out.Other, w)
 		}
 	}
 
-	return NodeWorkloads{
-		Deployments:  utils.MapValuesToSlice(deployments),
-		StatefulSets: utils.MapValuesToSlice(statefulSets),
-		System:       utils.MapKeysToSlice(system),
+	for _, list := range [][]Workload{out.Deployments, out.StatefulSets, out.DaemonSets, out.Jobs, out.Other} {
+		sortWorkloads(list)
 	}
+	sort.Strings(out.BarePods)
+	sort.Strings(out.System)
+
+	return out
+}
+
+func sortWorkloads(items []Workload) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Kind < b.Kind
+	})
 }
 
 func formatTaints(taints []v1.Taint) []string {
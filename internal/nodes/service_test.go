@@ -1,15 +1,31 @@
 package nodes
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/JNickson/cluster-telemetry-service/internal/testutil"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
 )
 
 type mapNodeInput struct {
 	Node      v1.Node
-	Usage     v1.ResourceList
+	Metrics   *metricsv1beta1.NodeMetrics
 	Workloads NodeWorkloads
 }
 
@@ -18,7 +34,136 @@ func TestMapNode(t *testing.T) {
 		t,
 		"testdata/mapNode",
 		func(input mapNodeInput) Node {
-			return mapNode(&input.Node, input.Usage, input.Workloads)
+			return mapNode(&input.Node, input.Metrics, input.Workloads)
 		},
 	)
 }
+
+type buildWorkloadsInput struct {
+	Pods        []v1.Pod            `json:"pods"`
+	ReplicaSets []appsv1.ReplicaSet `json:"replicaSets"`
+	Jobs        []batchv1.Job       `json:"jobs"`
+}
+
+func TestBuildWorkloadsForNode(t *testing.T) {
+	testutil.RunGoldenTest(
+		t,
+		"testdata/buildWorkloadsForNode",
+		func(input buildWorkloadsInput) NodeWorkloads {
+			pods := make([]*v1.Pod, 0, len(input.Pods))
+			for i := range input.Pods {
+				pods = append(pods, &input.Pods[i])
+			}
+			return buildWorkloadsForNode(pods, newTestResolver(t, input.ReplicaSets, input.Jobs))
+		},
+	)
+}
+
+type buildAllocationInput struct {
+	Node v1.Node  `json:"node"`
+	Pods []v1.Pod `json:"pods"`
+}
+
+func TestBuildAllocation(t *testing.T) {
+	testutil.RunGoldenTest(
+		t,
+		"testdata/buildAllocation",
+		func(input buildAllocationInput) Allocation {
+			pods := make([]*v1.Pod, 0, len(input.Pods))
+			for i := range input.Pods {
+				pods = append(pods, &input.Pods[i])
+			}
+			return buildAllocation(&input.Node, pods)
+		},
+	)
+}
+
+func newTestResolver(t *testing.T, replicaSets []appsv1.ReplicaSet, jobs []batchv1.Job) *workloads.OwnerResolver {
+	t.Helper()
+
+	rsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for i := range replicaSets {
+		require.NoError(t, rsIndexer.Add(&replicaSets[i]))
+	}
+
+	jobIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for i := range jobs {
+		require.NoError(t, jobIndexer.Add(&jobs[i]))
+	}
+
+	return workloads.NewOwnerResolver(
+		appsv1listers.NewReplicaSetLister(rsIndexer),
+		batchv1listers.NewJobLister(jobIndexer),
+	)
+}
+
+func TestBuildSnapshotWithoutNodeMetrics(t *testing.T) {
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, nodeIndexer.Add(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}))
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+
+	calls := 0
+	metrics := metricsfake.NewSimpleClientset()
+	metrics.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		return true, nil, errors.New("the server could not find the requested resource")
+	})
+
+	svc := NewNodeService(
+		corev1listers.NewNodeLister(nodeIndexer),
+		corev1listers.NewPodLister(podIndexer),
+		metrics,
+		newTestResolver(t, nil, nil),
+	)
+
+	out, err := svc.BuildSnapshot(context.Background())
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.False(t, out[0].MetricsAvailable)
+	require.Empty(t, out[0].CPU.Used)
+
+	// The failure backs off, so an immediate rebuild skips the metrics API.
+	_, err = svc.BuildSnapshot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+}
+
+func TestBuildSnapshotMetricsDisabled(t *testing.T) {
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, nodeIndexer.Add(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+			v1.ResourceCPU:  resource.MustParse("2"),
+			v1.ResourcePods: resource.MustParse("110"),
+		}},
+	}))
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, podIndexer.Add(&v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-0"},
+		Spec: v1.PodSpec{
+			NodeName: "node-1",
+			Containers: []v1.Container{{
+				Name: "app",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+				},
+			}},
+		},
+	}))
+
+	svc := NewNodeService(
+		corev1listers.NewNodeLister(nodeIndexer),
+		corev1listers.NewPodLister(podIndexer),
+		nil,
+		newTestResolver(t, nil, nil),
+	)
+
+	out, err := svc.BuildSnapshot(context.Background())
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.False(t, out[0].MetricsAvailable)
+
+	// Allocation comes from the pod lister, so it is there without metrics.
+	require.Equal(t, "500m", out[0].Allocation.CPU.Requests)
+	require.Equal(t, 1, out[0].Allocation.Pods.Count)
+}
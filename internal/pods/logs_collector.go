@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -21,11 +23,78 @@ import (
 type LogStreamOptions struct {
 	FromStart bool
 	TailLines *int64
+	// Container restricts a namespace stream to one container. When empty,
+	// every app container is streamed, plus init and ephemeral containers
+	// when InitContainers and EphemeralContainers are set.
+	Container           string
+	InitContainers      bool
+	EphemeralContainers bool
+	Filter              LogFilter
+	// PreviousOnRestart emits the last PreviousTailLines lines of the
+	// terminated container whenever a restart is detected.
+	PreviousOnRestart bool
+	PreviousTailLines int64
+}
+
+// PreviousLogOptions limits the terminated-container logs returned by
+// Previous. Nil fields are unbounded.
+type PreviousLogOptions struct {
+	TailLines    *int64
+	SinceSeconds *int64
+	Filter       LogFilter
+}
+
+// LogFilter selects which log lines are delivered. Every non-empty field must
+// match; the zero value matches every line.
+type LogFilter struct {
+	Include     *regexp.Regexp
+	Exclude     *regexp.Regexp
+	Contains    string
+	NotContains string
+}
+
+func (f LogFilter) Matches(message string) bool {
+	if f.Contains != "" && !strings.Contains(message, f.Contains) {
+		return false
+	}
+	if f.NotContains != "" && strings.Contains(message, f.NotContains) {
+		return false
+	}
+	if f.Include != nil && !f.Include.MatchString(message) {
+		return false
+	}
+	if f.Exclude != nil && f.Exclude.MatchString(message) {
+		return false
+	}
+	return true
+}
+
+// LogStreamScope selects the pods a namespace stream follows. Empty
+// Namespaces means all namespaces, a nil Selector matches every pod and empty
+// Names keeps every pod the selector matches.
+type LogStreamScope struct {
+	Namespaces []string
+	Selector   labels.Selector
+	Names      []string
+}
+
+func (s LogStreamScope) selector() labels.Selector {
+	if s.Selector == nil {
+		return labels.Everything()
+	}
+	return s.Selector
+}
+
+func (s LogStreamScope) matchesName(name string) bool {
+	return len(s.Names) == 0 || slices.Contains(s.Names, name)
 }
 
 type logOpenOptions struct {
-	SinceTime *time.Time
-	TailLines *int64
+	Container    string
+	Previous     bool
+	SinceTime    *time.Time
+	SinceSeconds *int64
+	TailLines    *int64
 }
 
 type PodLogsCollector struct {
@@ -36,8 +105,8 @@ type PodLogsCollector struct {
 	openStream        func(context.Context, string, string, logOpenOptions) (io.ReadCloser, error)
 	now               func() time.Time
 
-	mu        sync.Mutex
-	lastByPod map[string]time.Time
+	mu              sync.Mutex
+	lastByContainer map[string]time.Time
 }
 
 func NewPodLogsCollector(client kubernetes.Interface) *PodLogsCollector {
@@ -46,7 +115,7 @@ func NewPodLogsCollector(client kubernetes.Interface) *PodLogsCollector {
 		retryDelay:        2 * time.Second,
 		reconcileInterval: 5 * time.Second,
 		now:               time.Now,
-		lastByPod:         make(map[string]time.Time),
+		lastByContainer:   make(map[string]time.Time),
 	}
 
 	c.openStream = c.defaultOpenStream
@@ -54,6 +123,14 @@ func NewPodLogsCollector(client kubernetes.Interface) *PodLogsCollector {
 	return c
 }
 
+// WithIntervals sets how long to wait before reopening a failed stream and
+// how often the set of streamed pods is re-evaluated.
+func (c *PodLogsCollector) WithIntervals(retryDelay, reconcileInterval time.Duration) *PodLogsCollector {
+	c.retryDelay = retryDelay
+	c.reconcileInterval = reconcileInterval
+	return c
+}
+
 func (c *PodLogsCollector) WithPodLister(podLister corev1listers.PodLister) *PodLogsCollector {
 	c.podLister = podLister
 	return c
@@ -66,14 +143,20 @@ func (c *PodLogsCollector) defaultOpenStream(
 	openOpts logOpenOptions,
 ) (io.ReadCloser, error) {
 	logOpts := &v1.PodLogOptions{
-		Follow:     true,
+		Container:  openOpts.Container,
+		Follow:     !openOpts.Previous,
+		Previous:   openOpts.Previous,
 		Timestamps: true,
 	}
 
 	if openOpts.SinceTime != nil {
 		t := metav1.NewTime(openOpts.SinceTime.UTC())
 		logOpts.SinceTime = &t
-	} else if openOpts.TailLines != nil {
+	} else if openOpts.SinceSeconds != nil {
+		logOpts.SinceSeconds = openOpts.SinceSeconds
+	}
+
+	if openOpts.SinceTime == nil && openOpts.TailLines != nil {
 		logOpts.TailLines = openOpts.TailLines
 	}
 
@@ -84,15 +167,18 @@ func (c *PodLogsCollector) defaultOpenStream(
 	return req.Stream(ctx)
 }
 
+// Stream follows the logs of one container. An empty container lets the API
+// server pick the pod's only container.
 func (c *PodLogsCollector) Stream(
 	ctx context.Context,
 	namespace,
-	name string,
+	name,
+	container string,
 	options LogStreamOptions,
 	onRecord func(LogStreamRecord) error,
 ) error {
 	connected := false
-	key := namespace + "/" + name
+	key := namespace + "/" + name + "/" + container
 
 	for {
 		select {
@@ -101,7 +187,7 @@ func (c *PodLogsCollector) Stream(
 		default:
 		}
 
-		openOpts := logOpenOptions{}
+		openOpts := logOpenOptions{Container: container}
 		if sinceTime, ok := c.lastCursor(key); ok {
 			openOpts.SinceTime = &sinceTime
 		} else if options.FromStart {
@@ -117,7 +203,7 @@ func (c *PodLogsCollector) Stream(
 				return fmt.Errorf("open pod log stream: %w", err)
 			}
 
-			slog.Warn("pod log stream disconnected", "namespace", namespace, "pod", name, "error", err)
+			slog.Warn("pod log stream disconnected", "namespace", namespace, "pod", name, "container", container, "error", err)
 
 			if !sleepWithContext(ctx, c.retryDelay) {
 				return nil
@@ -138,10 +224,11 @@ func (c *PodLogsCollector) Stream(
 			}
 			c.updateCursor(key, lineTS)
 
-			if onRecord != nil {
+			if onRecord != nil && options.Filter.Matches(message) {
 				record := LogStreamRecord{
 					Namespace: namespace,
 					Pod:       name,
+					Container: container,
 					Message:   message,
 					Timestamp: lineTS,
 				}
@@ -156,7 +243,7 @@ func (c *PodLogsCollector) Stream(
 		_ = stream.Close()
 
 		if err := scanner.Err(); err != nil && ctx.Err() == nil {
-			slog.Warn("pod log scanner failed", "namespace", namespace, "pod", name, "error", err)
+			slog.Warn("pod log scanner failed", "namespace", namespace, "pod", name, "container", container, "error", err)
 		}
 
 		if !sleepWithContext(ctx, c.retryDelay) {
@@ -165,9 +252,59 @@ func (c *PodLogsCollector) Stream(
 	}
 }
 
+// Previous reads the logs of the previous, terminated instance of a
+// container. It does not follow; it returns once the logs are read.
+func (c *PodLogsCollector) Previous(
+	ctx context.Context,
+	namespace,
+	name,
+	container string,
+	options PreviousLogOptions,
+	onRecord func(LogStreamRecord) error,
+) error {
+	stream, err := c.openStream(ctx, namespace, name, logOpenOptions{
+		Container:    container,
+		Previous:     true,
+		SinceSeconds: options.SinceSeconds,
+		TailLines:    options.TailLines,
+	})
+	if err != nil {
+		return fmt.Errorf("open previous pod logs: %w", err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		lineTS, message, hasTS := parseTimestampedLine(scanner.Text())
+		if !hasTS {
+			lineTS = c.now().UTC()
+			message = scanner.Text()
+		}
+
+		if onRecord == nil || !options.Filter.Matches(message) {
+			continue
+		}
+
+		if err := onRecord(LogStreamRecord{
+			Namespace: namespace,
+			Pod:       name,
+			Container: container,
+			Previous:  true,
+			Message:   message,
+			Timestamp: lineTS,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
 func (c *PodLogsCollector) StreamNamespace(
 	ctx context.Context,
-	namespace string,
+	scope LogStreamScope,
 	options LogStreamOptions,
 	onRecord func(LogStreamRecord) error,
 ) error {
@@ -179,64 +316,117 @@ func (c *PodLogsCollector) StreamNamespace(
 	defer cancel()
 
 	records := make(chan LogStreamRecord, 256)
+	finished := make(chan activeStreamKey)
+
+	// Streams remove themselves from active when they end, so the next
+	// reconcile can restart containers that were not running yet.
+	active := make(map[string]activeStream)
+	var nextID uint64
 
-	active := make(map[string]context.CancelFunc)
 	stopAll := func() {
-		for key, stop := range active {
-			stop()
+		for key, stream := range active {
+			stream.cancel()
 			delete(active, key)
 		}
 	}
 
-	startPod := func(ns, name string) {
-		key := ns + "/" + name
+	startContainer := func(ns, name, container string) {
+		key := ns + "/" + name + "/" + container
 		if _, exists := active[key]; exists {
 			return
 		}
 
-		podCtx, podCancel := context.WithCancel(ctx)
-		active[key] = podCancel
+		nextID++
+		id := nextID
+
+		streamCtx, streamCancel := context.WithCancel(ctx)
+		active[key] = activeStream{id: id, cancel: streamCancel}
 
 		go func() {
-			err := c.Stream(podCtx, ns, name, options, func(record LogStreamRecord) error {
+			err := c.Stream(streamCtx, ns, name, container, options, func(record LogStreamRecord) error {
 				select {
-				case <-podCtx.Done():
-					return podCtx.Err()
+				case <-streamCtx.Done():
+					return streamCtx.Err()
 				case records <- record:
 					return nil
 				}
 			})
 
-			if err != nil && podCtx.Err() == nil {
-				slog.Warn("pod namespace stream ended", "namespace", ns, "pod", name, "error", err)
+			if err != nil && streamCtx.Err() == nil {
+				slog.Warn("pod namespace stream ended", "namespace", ns, "pod", name, "container", container, "error", err)
+			}
+
+			select {
+			case finished <- activeStreamKey{key: key, id: id}:
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	// restarts holds the last seen restart count per container so a
+	// restart can be detected between reconciles.
+	restarts := make(map[string]int32)
+
+	emitPrevious := func(ns, name, container string) {
+		tail := options.PreviousTailLines
+		go func() {
+			err := c.Previous(ctx, ns, name, container, PreviousLogOptions{TailLines: &tail, Filter: options.Filter}, func(record LogStreamRecord) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case records <- record:
+					return nil
+				}
+			})
+
+			if err != nil && ctx.Err() == nil {
+				slog.Warn("failed to read previous container logs", "namespace", ns, "pod", name, "container", container, "error", err)
 			}
 		}()
 	}
 
 	reconcile := func() {
-		pods, err := c.podLister.Pods(namespace).List(labels.Everything())
+		pods, err := c.listScopePods(scope)
 		if err != nil {
-			slog.Warn("failed to list pods for namespace stream", "namespace", namespace, "error", err)
+			slog.Warn("failed to list pods for namespace stream", "namespaces", scope.Namespaces, "error", err)
 			return
 		}
 
 		seen := make(map[string]struct{}, len(pods))
 		for _, pod := range pods {
-			if pod.Spec.NodeName == "" {
+			if pod.Spec.NodeName == "" || !scope.matchesName(pod.Name) {
 				continue
 			}
 
-			key := pod.Namespace + "/" + pod.Name
-			seen[key] = struct{}{}
-			startPod(pod.Namespace, pod.Name)
+			for _, container := range logContainers(pod, options) {
+				key := pod.Namespace + "/" + pod.Name + "/" + container
+				seen[key] = struct{}{}
+				startContainer(pod.Namespace, pod.Name, container)
+
+				if !options.PreviousOnRestart {
+					continue
+				}
+
+				count := containerRestartCount(pod, container)
+				if last, ok := restarts[key]; ok && count > last {
+					emitPrevious(pod.Namespace, pod.Name, container)
+				}
+				restarts[key] = count
+			}
 		}
 
-		for key, stop := range active {
+		for key, stream := range active {
 			if _, ok := seen[key]; !ok {
-				stop()
+				stream.cancel()
 				delete(active, key)
 			}
 		}
+
+		for key := range restarts {
+			if _, ok := seen[key]; !ok {
+				delete(restarts, key)
+			}
+		}
 	}
 
 	reconcile()
@@ -250,6 +440,11 @@ func (c *PodLogsCollector) StreamNamespace(
 			return nil
 		case <-ticker.C:
 			reconcile()
+		case done := <-finished:
+			if stream, ok := active[done.key]; ok && stream.id == done.id {
+				stream.cancel()
+				delete(active, done.key)
+			}
 		case record := <-records:
 			if onRecord != nil {
 				if err := onRecord(record); err != nil {
@@ -261,6 +456,117 @@ func (c *PodLogsCollector) StreamNamespace(
 	}
 }
 
+func (c *PodLogsCollector) listScopePods(scope LogStreamScope) ([]*v1.Pod, error) {
+	if len(scope.Namespaces) == 0 {
+		return c.podLister.List(scope.selector())
+	}
+
+	var out []*v1.Pod
+	for _, ns := range scope.Namespaces {
+		pods, err := c.podLister.Pods(ns).List(scope.selector())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, pods...)
+	}
+
+	return out, nil
+}
+
+type activeStream struct {
+	id     uint64
+	cancel context.CancelFunc
+}
+
+type activeStreamKey struct {
+	key string
+	id  uint64
+}
+
+// logContainers returns the containers of pod to stream. Containers that
+// are still waiting to start are skipped until a later reconcile.
+func logContainers(pod *v1.Pod, options LogStreamOptions) []string {
+	var names []string
+
+	switch {
+	case options.Container != "":
+		if hasContainer(pod, options.Container) {
+			names = append(names, options.Container)
+		}
+	default:
+		for _, c := range pod.Spec.Containers {
+			names = append(names, c.Name)
+		}
+		if options.InitContainers {
+			for _, c := range pod.Spec.InitContainers {
+				names = append(names, c.Name)
+			}
+		}
+		if options.EphemeralContainers {
+			for _, c := range pod.Spec.EphemeralContainers {
+				names = append(names, c.Name)
+			}
+		}
+	}
+
+	out := names[:0]
+	for _, name := range names {
+		if !containerWaiting(pod, name) {
+			out = append(out, name)
+		}
+	}
+
+	return out
+}
+
+func hasContainer(pod *v1.Pod, name string) bool {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == name {
+			return true
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == name {
+			return true
+		}
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containerWaiting(pod *v1.Pod, name string) bool {
+	cs, ok := findContainerStatus(pod, name)
+	// A restarted container still has logs from its last run.
+	return ok && cs.State.Waiting != nil && cs.LastTerminationState.Terminated == nil
+}
+
+func containerRestartCount(pod *v1.Pod, name string) int32 {
+	cs, _ := findContainerStatus(pod, name)
+	return cs.RestartCount
+}
+
+func findContainerStatus(pod *v1.Pod, name string) (v1.ContainerStatus, bool) {
+	statuses := [][]v1.ContainerStatus{
+		pod.Status.ContainerStatuses,
+		pod.Status.InitContainerStatuses,
+		pod.Status.EphemeralContainerStatuses,
+	}
+
+	for _, list := range statuses {
+		for _, cs := range list {
+			if cs.Name == name {
+				return cs, true
+			}
+		}
+	}
+
+	return v1.ContainerStatus{}, false
+}
+
 func parseTimestampedLine(line string) (time.Time, string, bool) {
 	space := strings.IndexByte(line, ' ')
 	if space <= 0 {
@@ -283,18 +589,18 @@ func (c *PodLogsCollector) updateCursor(key string, t time.Time) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if prev, ok := c.lastByPod[key]; ok && !t.After(prev) {
+	if prev, ok := c.lastByContainer[key]; ok && !t.After(prev) {
 		return
 	}
 
-	c.lastByPod[key] = t
+	c.lastByContainer[key] = t
 }
 
 func (c *PodLogsCollector) lastCursor(key string) (time.Time, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	last, ok := c.lastByPod[key]
+	last, ok := c.lastByContainer[key]
 	if !ok {
 		return time.Time{}, false
 	}
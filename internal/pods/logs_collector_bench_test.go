@@ -36,6 +36,7 @@ func runPodLogsCollectorStreamBenchmark(b *testing.B, lines int) {
 			context.Background(),
 			"default",
 			"api-0",
+			"app",
 			LogStreamOptions{FromStart: true},
 			func(LogStreamRecord) error {
 				count++
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"regexp"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -54,7 +55,7 @@ func TestPodLogsCollectorStream(t *testing.T) {
 
 			var got []LogStreamRecord
 
-			err := collector.Stream(ctx, "default", "api-0", LogStreamOptions{}, func(record LogStreamRecord) error {
+			err := collector.Stream(ctx, "default", "api-0", "app", LogStreamOptions{}, func(record LogStreamRecord) error {
 				got = append(got, record)
 				if len(got) >= len(tt.wantMessages) && len(tt.wantMessages) > 0 {
 					return errors.New("stop")
@@ -74,6 +75,7 @@ func TestPodLogsCollectorStream(t *testing.T) {
 			for i, msg := range tt.wantMessages {
 				require.Equal(t, "default", got[i].Namespace)
 				require.Equal(t, "api-0", got[i].Pod)
+				require.Equal(t, "app", got[i].Container)
 				require.Equal(t, msg, got[i].Message)
 				require.Equal(t, time.Date(2026, time.February, 19, 12, 0, i, 0, time.UTC), got[i].Timestamp)
 			}
@@ -81,6 +83,66 @@ func TestPodLogsCollectorStream(t *testing.T) {
 	}
 }
 
+func TestLogFilterMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  LogFilter
+		message string
+		want    bool
+	}{
+		{name: "zero filter matches", message: "anything", want: true},
+		{name: "contains matches", filter: LogFilter{Contains: "GET"}, message: "GET /api", want: true},
+		{name: "contains misses", filter: LogFilter{Contains: "POST"}, message: "GET /api", want: false},
+		{name: "notContains drops", filter: LogFilter{NotContains: "healthz"}, message: "GET /healthz", want: false},
+		{name: "include matches", filter: LogFilter{Include: regexp.MustCompile(`status=5\d\d`)}, message: "status=503", want: true},
+		{name: "include misses", filter: LogFilter{Include: regexp.MustCompile(`status=5\d\d`)}, message: "status=200", want: false},
+		{name: "exclude drops", filter: LogFilter{Exclude: regexp.MustCompile(`^DEBUG`)}, message: "DEBUG noisy", want: false},
+		{
+			name:    "all conditions must hold",
+			filter:  LogFilter{Include: regexp.MustCompile(`error`), NotContains: "retrying"},
+			message: "error: retrying",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.filter.Matches(tt.message))
+		})
+	}
+}
+
+func TestPodLogsCollectorStreamAppliesFilter(t *testing.T) {
+	collector := NewPodLogsCollector(nil)
+	collector.retryDelay = time.Millisecond
+
+	var lastOpen logOpenOptions
+	collector.openStream = func(_ context.Context, _ string, _ string, opts logOpenOptions) (io.ReadCloser, error) {
+		lastOpen = opts
+		return io.NopCloser(strings.NewReader(
+			"2026-02-19T12:00:00Z GET /healthz\n2026-02-19T12:00:01Z GET /api\n2026-02-19T12:00:02Z POST /api\n",
+		)), nil
+	}
+
+	options := LogStreamOptions{
+		Filter: LogFilter{Include: regexp.MustCompile(`/api$`)},
+	}
+
+	var got []string
+	err := collector.Stream(context.Background(), "default", "api-0", "app", options, func(record LogStreamRecord) error {
+		got = append(got, record.Message)
+		if len(got) == 2 {
+			return errors.New("done")
+		}
+		return nil
+	})
+
+	require.Error(t, err)
+	require.Equal(t, "done", err.Error())
+	require.Equal(t, []string{"GET /api", "POST /api"}, got)
+	require.Equal(t, "app", lastOpen.Container)
+}
+
 func TestPodLogsCollectorStreamReconnects(t *testing.T) {
 	collector := NewPodLogsCollector(nil)
 	collector.retryDelay = time.Millisecond
@@ -98,7 +160,7 @@ func TestPodLogsCollectorStreamReconnects(t *testing.T) {
 	defer cancel()
 
 	var got []string
-	err := collector.Stream(ctx, "default", "api-0", LogStreamOptions{}, func(record LogStreamRecord) error {
+	err := collector.Stream(ctx, "default", "api-0", "app", LogStreamOptions{}, func(record LogStreamRecord) error {
 		got = append(got, record.Message)
 		if len(got) == 2 {
 			return errors.New("done")
@@ -122,14 +184,14 @@ func TestPodLogsCollectorStreamUsesCursorForNextSession(t *testing.T) {
 		return io.NopCloser(strings.NewReader("2026-02-19T12:00:00Z one\n")), nil
 	}
 
-	err := collector.Stream(context.Background(), "default", "api-0", LogStreamOptions{FromStart: true}, func(record LogStreamRecord) error {
+	err := collector.Stream(context.Background(), "default", "api-0", "app", LogStreamOptions{FromStart: true}, func(record LogStreamRecord) error {
 		return errors.New("done")
 	})
 	require.Error(t, err)
 	require.Equal(t, "done", err.Error())
 	require.Nil(t, lastOpen.SinceTime)
 
-	err = collector.Stream(context.Background(), "default", "api-0", LogStreamOptions{}, func(record LogStreamRecord) error {
+	err = collector.Stream(context.Background(), "default", "api-0", "app", LogStreamOptions{}, func(record LogStreamRecord) error {
 		return errors.New("done-2")
 	})
 	require.Error(t, err)
@@ -139,8 +201,8 @@ func TestPodLogsCollectorStreamUsesCursorForNextSession(t *testing.T) {
 
 func TestPodLogsCollectorStreamNamespace(t *testing.T) {
 	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
-	require.NoError(t, indexer.Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-0", Namespace: "default"}, Spec: v1.PodSpec{NodeName: "node-a"}}))
-	require.NoError(t, indexer.Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default"}, Spec: v1.PodSpec{NodeName: "node-b"}}))
+	require.NoError(t, indexer.Add(newTestPod("default", "api-0", "node-a", "app")))
+	require.NoError(t, indexer.Add(newTestPod("default", "api-1", "node-b", "app")))
 
 	collector := NewPodLogsCollector(nil).WithPodLister(newPodLister(indexer))
 	collector.retryDelay = time.Millisecond
@@ -151,7 +213,7 @@ func TestPodLogsCollectorStreamNamespace(t *testing.T) {
 	}
 
 	seen := map[string]bool{}
-	err := collector.StreamNamespace(context.Background(), "default", LogStreamOptions{FromStart: true}, func(record LogStreamRecord) error {
+	err := collector.StreamNamespace(context.Background(), LogStreamScope{Namespaces: []string{"default"}}, LogStreamOptions{FromStart: true}, func(record LogStreamRecord) error {
 		seen[record.Pod] = true
 		if len(seen) == 2 {
 			return errors.New("done")
@@ -165,6 +227,225 @@ func TestPodLogsCollectorStreamNamespace(t *testing.T) {
 	require.True(t, seen["api-1"])
 }
 
+func TestPodLogsCollectorStreamNamespaceContainers(t *testing.T) {
+	pod := newTestPod("default", "api-0", "node-a", "app", "istio-proxy")
+	pod.Spec.InitContainers = []v1.Container{{Name: "migrate"}}
+	pod.Spec.EphemeralContainers = []v1.EphemeralContainer{{EphemeralContainerCommon: v1.EphemeralContainerCommon{Name: "debugger"}}}
+
+	waiting := newTestPod("default", "api-1", "node-a", "app")
+	waiting.Status.ContainerStatuses = []v1.ContainerStatus{
+		{Name: "app", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+	}
+
+	tests := []struct {
+		name    string
+		options LogStreamOptions
+		want    []string
+	}{
+		{
+			name: "app containers by default",
+			want: []string{"api-0/app", "api-0/istio-proxy"},
+		},
+		{
+			name:    "includes init and ephemeral containers when asked",
+			options: LogStreamOptions{InitContainers: true, EphemeralContainers: true},
+			want:    []string{"api-0/app", "api-0/debugger", "api-0/istio-proxy", "api-0/migrate"},
+		},
+		{
+			name:    "selects a single container",
+			options: LogStreamOptions{Container: "istio-proxy"},
+			want:    []string{"api-0/istio-proxy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			require.NoError(t, indexer.Add(pod))
+			require.NoError(t, indexer.Add(waiting))
+
+			collector := NewPodLogsCollector(nil).WithPodLister(newPodLister(indexer))
+			collector.retryDelay = time.Hour
+			collector.reconcileInterval = time.Hour
+
+			collector.openStream = func(_ context.Context, _ string, name string, opts logOpenOptions) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("2026-02-19T12:00:00Z from-" + opts.Container + "\n")), nil
+			}
+
+			var seen []string
+			err := collector.StreamNamespace(context.Background(), LogStreamScope{Namespaces: []string{"default"}}, tt.options, func(record LogStreamRecord) error {
+				require.Equal(t, "from-"+record.Container, record.Message)
+				seen = append(seen, record.Pod+"/"+record.Container)
+				if len(seen) == len(tt.want) {
+					return errors.New("done")
+				}
+				return nil
+			})
+
+			require.Error(t, err)
+			require.Equal(t, "done", err.Error())
+			require.ElementsMatch(t, tt.want, seen)
+		})
+	}
+}
+
+func TestPodLogsCollectorStreamNamespaceScope(t *testing.T) {
+	checkoutA := newTestPod("staging-a", "checkout-0", "node-a", "app")
+	checkoutA.Labels = map[string]string{"app": "checkout"}
+	checkoutB := newTestPod("staging-b", "checkout-1", "node-a", "app")
+	checkoutB.Labels = map[string]string{"app": "checkout"}
+	checkoutProd := newTestPod("prod", "checkout-2", "node-a", "app")
+	checkoutProd.Labels = map[string]string{"app": "checkout"}
+	cart := newTestPod("staging-a", "cart-0", "node-a", "app")
+	cart.Labels = map[string]string{"app": "cart"}
+
+	checkout := labels.SelectorFromSet(labels.Set{"app": "checkout"})
+
+	tests := []struct {
+		name  string
+		scope LogStreamScope
+		want  []string
+	}{
+		{
+			name:  "selector across listed namespaces",
+			scope: LogStreamScope{Namespaces: []string{"staging-a", "staging-b"}, Selector: checkout},
+			want:  []string{"staging-a/checkout-0", "staging-b/checkout-1"},
+		},
+		{
+			name:  "selector across all namespaces",
+			scope: LogStreamScope{Selector: checkout},
+			want:  []string{"prod/checkout-2", "staging-a/checkout-0", "staging-b/checkout-1"},
+		},
+		{
+			name:  "explicit pod names",
+			scope: LogStreamScope{Namespaces: []string{"staging-a"}, Names: []string{"cart-0"}},
+			want:  []string{"staging-a/cart-0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, pod := range []*v1.Pod{checkoutA, checkoutB, checkoutProd, cart} {
+				require.NoError(t, indexer.Add(pod))
+			}
+
+			collector := NewPodLogsCollector(nil).WithPodLister(newPodLister(indexer))
+			collector.retryDelay = time.Hour
+			collector.reconcileInterval = time.Hour
+
+			var opened atomic.Int32
+			collector.openStream = func(context.Context, string, string, logOpenOptions) (io.ReadCloser, error) {
+				opened.Add(1)
+				return io.NopCloser(strings.NewReader("2026-02-19T12:00:00Z hi\n")), nil
+			}
+
+			var seen []string
+			err := collector.StreamNamespace(context.Background(), tt.scope, LogStreamOptions{}, func(record LogStreamRecord) error {
+				seen = append(seen, record.Namespace+"/"+record.Pod)
+				if len(seen) == len(tt.want) {
+					return errors.New("done")
+				}
+				return nil
+			})
+
+			require.Error(t, err)
+			require.ElementsMatch(t, tt.want, seen)
+			require.Equal(t, int32(len(tt.want)), opened.Load())
+		})
+	}
+}
+
+func TestPodLogsCollectorPrevious(t *testing.T) {
+	collector := NewPodLogsCollector(nil)
+
+	var lastOpen logOpenOptions
+	collector.openStream = func(_ context.Context, _ string, _ string, opts logOpenOptions) (io.ReadCloser, error) {
+		lastOpen = opts
+		return io.NopCloser(strings.NewReader("2026-02-19T12:00:00Z starting\n2026-02-19T12:00:01Z panic: boom\n")), nil
+	}
+
+	tail := int64(20)
+	since := int64(300)
+
+	var got []LogStreamRecord
+	err := collector.Previous(context.Background(), "default", "api-0", "app", PreviousLogOptions{
+		TailLines:    &tail,
+		SinceSeconds: &since,
+		Filter:       LogFilter{Contains: "panic"},
+	}, func(record LogStreamRecord) error {
+		got = append(got, record)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.True(t, lastOpen.Previous)
+	require.Equal(t, "app", lastOpen.Container)
+	require.Equal(t, &tail, lastOpen.TailLines)
+	require.Equal(t, &since, lastOpen.SinceSeconds)
+	require.Len(t, got, 1)
+	require.Equal(t, "panic: boom", got[0].Message)
+	require.Equal(t, "app", got[0].Container)
+	require.True(t, got[0].Previous)
+}
+
+func TestPodLogsCollectorStreamNamespaceEmitsPreviousOnRestart(t *testing.T) {
+	pod := newTestPod("default", "api-0", "node-a", "app")
+	pod.Status.ContainerStatuses = []v1.ContainerStatus{{Name: "app", RestartCount: 1}}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(pod))
+
+	collector := NewPodLogsCollector(nil).WithPodLister(newPodLister(indexer))
+	collector.retryDelay = time.Hour
+	collector.reconcileInterval = 10 * time.Millisecond
+
+	var previousTail atomic.Int64
+	collector.openStream = func(_ context.Context, _ string, _ string, opts logOpenOptions) (io.ReadCloser, error) {
+		if opts.Previous {
+			previousTail.Store(*opts.TailLines)
+			return io.NopCloser(strings.NewReader("2026-02-19T11:59:59Z panic: boom\n")), nil
+		}
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+
+	restarted := pod.DeepCopy()
+	restarted.Status.ContainerStatuses[0].RestartCount = 2
+
+	options := LogStreamOptions{PreviousOnRestart: true, PreviousTailLines: 5}
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = indexer.Update(restarted)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var got LogStreamRecord
+	err := collector.StreamNamespace(ctx, LogStreamScope{Namespaces: []string{"default"}}, options, func(record LogStreamRecord) error {
+		got = record
+		return errors.New("done")
+	})
+
+	require.Error(t, err)
+	require.Equal(t, "done", err.Error())
+	require.True(t, got.Previous)
+	require.Equal(t, "panic: boom", got.Message)
+	require.Equal(t, int64(5), previousTail.Load())
+}
+
+func newTestPod(namespace, name, node string, containers ...string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       v1.PodSpec{NodeName: node},
+	}
+	for _, c := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: c})
+	}
+	return pod
+}
+
 func newPodLister(indexer cache.Indexer) *podListerAdapter {
 	return &podListerAdapter{indexer: indexer}
 }
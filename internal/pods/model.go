@@ -1,6 +1,10 @@
 package pods
 
-import "time"
+import (
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
+)
 
 type Pod struct {
 	Name      string `json:"name"`
@@ -12,20 +16,74 @@ type Pod struct {
 	Restarts int32  `json:"restarts"`
 	Age      string `json:"age"`
 
-	Containers []Container `json:"containers"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Labels    map[string]string `json:"labels"`
+
+	QOSClass string `json:"qosClass"`
+
+	Containers     []Container `json:"containers"`
+	InitContainers []Container `json:"initContainers"`
+
+	// Usage is nil when metrics.k8s.io has no sample for the pod.
+	Usage *ResourceUsage `json:"usage,omitempty"`
+
+	// Warnings are the most recent Warning events reported for the pod.
+	Warnings []events.Warning `json:"warnings,omitempty"`
 }
 
 type Container struct {
-	Name  string `json:"name"`
-	Ready bool   `json:"ready"`
+	Name     string `json:"name"`
+	Ready    bool   `json:"ready"`
+	Restarts int32  `json:"restarts"`
+
+	// Sidecar marks a restartable init container (restartPolicy: Always).
+	Sidecar bool `json:"sidecar,omitempty"`
+
+	Image   string         `json:"image"`
+	ImageID string         `json:"imageID"`
+	State   ContainerState `json:"state"`
 
 	CPURequest    string `json:"cpuRequest"`
 	MemoryRequest string `json:"memoryRequest"`
+	CPULimit      string `json:"cpuLimit"`
+	MemoryLimit   string `json:"memoryLimit"`
+
+	EphemeralStorageRequest string `json:"ephemeralStorageRequest"`
+	EphemeralStorageLimit   string `json:"ephemeralStorageLimit"`
+
+	// ExtendedRequests and ExtendedLimits hold every other resource, such
+	// as nvidia.com/gpu or hugepages-2Mi, keyed by resource name.
+	ExtendedRequests map[string]string `json:"extendedRequests,omitempty"`
+	ExtendedLimits   map[string]string `json:"extendedLimits,omitempty"`
+
+	Usage *ResourceUsage `json:"usage,omitempty"`
+}
+
+// ResourceUsage is the live CPU and memory usage reported by metrics.k8s.io.
+// Ratios are nil when there is no request or limit to compare against.
+type ResourceUsage struct {
+	CPU    string `json:"cpu"`
+	Memory string `json:"memory"`
+
+	CPURequestRatio    *float64 `json:"cpuRequestRatio,omitempty"`
+	CPULimitRatio      *float64 `json:"cpuLimitRatio,omitempty"`
+	MemoryRequestRatio *float64 `json:"memoryRequestRatio,omitempty"`
+	MemoryLimitRatio   *float64 `json:"memoryLimitRatio,omitempty"`
+}
+
+type ContainerState struct {
+	// Status is waiting, running or terminated; empty when unknown.
+	Status   string `json:"status"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+	ExitCode *int32 `json:"exitCode,omitempty"`
 }
 
 type LogStreamRecord struct {
 	Namespace string    `json:"namespace"`
 	Pod       string    `json:"pod"`
+	Container string    `json:"container"`
+	Previous  bool      `json:"previous,omitempty"`
 	Message   string    `json:"message"`
 	Timestamp time.Time `json:"timestamp"`
 }
@@ -3,46 +3,96 @@ package pods
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
 	"github.com/JNickson/cluster-telemetry-service/internal/utils"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	corev1listers "k8s.io/client-go/listers/core/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 type Service interface {
 	BuildSnapshot(ctx context.Context) ([]Pod, error)
 	StreamNamespaceLogs(
 		ctx context.Context,
-		namespace string,
+		scope LogStreamScope,
 		options LogStreamOptions,
 		onRecord func(LogStreamRecord) error,
 	) error
+	PreviousLogs(
+		ctx context.Context,
+		namespace,
+		name,
+		container string,
+		options PreviousLogOptions,
+		onRecord func(LogStreamRecord) error,
+	) error
 }
 
 type podLogsStreamer interface {
 	StreamNamespace(
 		ctx context.Context,
-		namespace string,
+		scope LogStreamScope,
 		options LogStreamOptions,
 		onRecord func(LogStreamRecord) error,
 	) error
+	Previous(
+		ctx context.Context,
+		namespace,
+		name,
+		container string,
+		options PreviousLogOptions,
+		onRecord func(LogStreamRecord) error,
+	) error
 }
 
+const (
+	maxWarningsPerPod = 5
+)
+
 type PodService struct {
-	podLister     corev1listers.PodLister
-	logsCollector podLogsStreamer
+	podLister      corev1listers.PodLister
+	metricsClient  metricsclient.Interface
+	metricsBackoff *utils.Backoff
+	metricsCache   *utils.Cache[map[string]*metricsv1beta1.PodMetrics]
+	events         *events.Buffer
+	logsCollector  podLogsStreamer
 }
 
 func NewPodService(
 	podLister corev1listers.PodLister,
 	kubeClient kubernetes.Interface,
+	metricsClient metricsclient.Interface,
 ) *PodService {
 	return &PodService{
-		podLister:     podLister,
-		logsCollector: NewPodLogsCollector(kubeClient).WithPodLister(podLister),
+		podLister:      podLister,
+		metricsClient:  metricsClient,
+		metricsBackoff: utils.NewBackoff(),
+		metricsCache:   utils.NewCache[map[string]*metricsv1beta1.PodMetrics](),
+		logsCollector:  NewPodLogsCollector(kubeClient).WithPodLister(podLister),
+	}
+}
+
+// WithEvents attaches the most recent Warning events from buf to each pod
+// in the snapshot.
+func (s *PodService) WithEvents(buf *events.Buffer) *PodService {
+	s.events = buf
+	return s
+}
+
+// WithLogIntervals sets the retry delay and reconcile interval of the log
+// collector.
+func (s *PodService) WithLogIntervals(retryDelay, reconcileInterval time.Duration) *PodService {
+	if c, ok := s.logsCollector.(*PodLogsCollector); ok {
+		c.WithIntervals(retryDelay, reconcileInterval)
 	}
+	return s
 }
 
 func (s *PodService) BuildSnapshot(ctx context.Context) ([]Pod, error) {
@@ -52,6 +102,15 @@ func (s *PodService) BuildSnapshot(ctx context.Context) ([]Pod, error) {
 		return nil, err
 	}
 
+	// Usage is best effort: without metrics-server the snapshot is still
+	// useful, it just reports requests and limits only.
+	metricsByPod := s.podMetrics(ctx)
+
+	var warnings map[string][]events.Warning
+	if s.events != nil {
+		warnings = s.events.WarningsByObject("Pod", maxWarningsPerPod)
+	}
+
 	out := make([]Pod, 0, len(list))
 
 	for _, p := range list {
@@ -64,26 +123,73 @@ func (s *PodService) BuildSnapshot(ctx context.Context) ([]Pod, error) {
 			continue
 		}
 
-		out = append(out, mapPod(*p))
+		pod := mapPodWithUsage(*p, metricsByPod[p.Namespace+"/"+p.Name])
+		pod.Warnings = warnings[events.ObjectKey(p.Namespace, p.Name)]
+
+		out = append(out, pod)
 	}
 
 	return out, nil
 }
 
+// podMetrics lists pod usage from metrics.k8s.io, reusing the last
+// successful list until it is older than the cache TTL. Failures back off.
+func (s *PodService) podMetrics(ctx context.Context) map[string]*metricsv1beta1.PodMetrics {
+	if s.metricsClient == nil {
+		return nil
+	}
+	if cached, ok := s.metricsCache.Get(); ok {
+		return cached
+	}
+	if !s.metricsBackoff.Ready() {
+		return nil
+	}
+
+	list, err := s.metricsClient.MetricsV1beta1().
+		PodMetricses(metav1.NamespaceAll).
+		List(ctx, metav1.ListOptions{})
+	if err != nil {
+		wait := s.metricsBackoff.Failure()
+		slog.Warn("pod metrics unavailable", "error", err, "retryIn", wait)
+		return nil
+	}
+	s.metricsBackoff.Success()
+
+	out := make(map[string]*metricsv1beta1.PodMetrics, len(list.Items))
+	for i := range list.Items {
+		m := &list.Items[i]
+		out[m.Namespace+"/"+m.Name] = m
+	}
+	s.metricsCache.Set(out)
+
+	return out
+}
+
 func (s *PodService) StreamNamespaceLogs(
 	ctx context.Context,
-	namespace string,
+	scope LogStreamScope,
 	options LogStreamOptions,
 	onRecord func(LogStreamRecord) error,
 ) error {
-	return s.logsCollector.StreamNamespace(ctx, namespace, options, onRecord)
+	return s.logsCollector.StreamNamespace(ctx, scope, options, onRecord)
+}
+
+func (s *PodService) PreviousLogs(
+	ctx context.Context,
+	namespace,
+	name,
+	container string,
+	options PreviousLogOptions,
+	onRecord func(LogStreamRecord) error,
+) error {
+	return s.logsCollector.Previous(ctx, namespace, name, container, options, onRecord)
 }
 
 func mapPod(p v1.Pod) Pod {
 	var (
-		ready      = true
-		restarts   int32
-		containers []Container
+		ready          = true
+		containers     []Container
+		initContainers []Container
 	)
 
 	if len(p.Status.ContainerStatuses) == 0 {
@@ -98,41 +204,140 @@ func mapPod(p v1.Pod) Pod {
 
 	for _, cs := range p.Status.ContainerStatuses {
 
-		specContainer := specMap[cs.Name]
+		containers = append(containers, mapContainer(specMap[cs.Name], cs))
 
-		var cpuReq, memReq string
-
-		if req := specContainer.Resources.Requests; req != nil {
-			if cpu, ok := req[v1.ResourceCPU]; ok {
-				cpuReq = fmt.Sprintf("%dm", cpu.MilliValue())
-			}
-			if mem, ok := req[v1.ResourceMemory]; ok {
-				memReq = fmt.Sprintf("%dMi", mem.Value()/(1024*1024))
-			}
+		if !cs.Ready {
+			ready = false
 		}
+	}
 
-		containers = append(containers, Container{
-			Name:          cs.Name,
-			Ready:         cs.Ready,
-			CPURequest:    cpuReq,
-			MemoryRequest: memReq,
-		})
+	initSpecMap := make(map[string]v1.Container)
+	for _, c := range p.Spec.InitContainers {
+		initSpecMap[c.Name] = c
+	}
 
+	for _, cs := range p.Status.InitContainerStatuses {
+		initContainers = append(initContainers, mapContainer(initSpecMap[cs.Name], cs))
+	}
+
+	return Pod{
+		Name:           p.Name,
+		Namespace:      p.Namespace,
+		Node:           p.Spec.NodeName,
+		Phase:          string(p.Status.Phase),
+		Ready:          ready,
+		Restarts:       RestartCount(&p),
+		Age:            utils.AgeSince(p.CreationTimestamp.Time),
+		CreatedAt:      p.CreationTimestamp.UTC(),
+		Labels:         p.Labels,
+		QOSClass:       string(p.Status.QOSClass),
+		Containers:     containers,
+		InitContainers: initContainers,
+	}
+}
+
+// RestartCount sums the restarts of the app containers and sidecars of p.
+// Sidecars run for the life of the pod, so their restarts count towards the
+// pod like app containers do; regular init containers are left out.
+func RestartCount(p *v1.Pod) int32 {
+	var restarts int32
+	for _, cs := range p.Status.ContainerStatuses {
 		restarts += cs.RestartCount
+	}
 
-		if !cs.Ready {
-			ready = false
+	sidecars := make(map[string]bool, len(p.Spec.InitContainers))
+	for _, c := range p.Spec.InitContainers {
+		sidecars[c.Name] = isSidecar(c)
+	}
+	for _, cs := range p.Status.InitContainerStatuses {
+		if sidecars[cs.Name] {
+			restarts += cs.RestartCount
 		}
 	}
 
-	return Pod{
-		Name:       p.Name,
-		Namespace:  p.Namespace,
-		Node:       p.Spec.NodeName,
-		Phase:      string(p.Status.Phase),
-		Ready:      ready,
-		Restarts:   restarts,
-		Age:        utils.AgeSince(p.CreationTimestamp.Time),
-		Containers: containers,
+	return restarts
+}
+
+func mapContainer(spec v1.Container, cs v1.ContainerStatus) Container {
+	requests := spec.Resources.Requests
+	limits := spec.Resources.Limits
+
+	return Container{
+		Name:     cs.Name,
+		Ready:    cs.Ready,
+		Restarts: cs.RestartCount,
+		Sidecar:  isSidecar(spec),
+
+		Image:   cs.Image,
+		ImageID: cs.ImageID,
+		State:   mapContainerState(cs.State),
+
+		CPURequest:    formatCPU(requests, v1.ResourceCPU),
+		MemoryRequest: formatMemory(requests, v1.ResourceMemory),
+		CPULimit:      formatCPU(limits, v1.ResourceCPU),
+		MemoryLimit:   formatMemory(limits, v1.ResourceMemory),
+
+		EphemeralStorageRequest: formatMemory(requests, v1.ResourceEphemeralStorage),
+		EphemeralStorageLimit:   formatMemory(limits, v1.ResourceEphemeralStorage),
+
+		ExtendedRequests: extendedResources(requests),
+		ExtendedLimits:   extendedResources(limits),
+	}
+}
+
+func mapContainerState(s v1.ContainerState) ContainerState {
+	switch {
+	case s.Running != nil:
+		return ContainerState{Status: "running"}
+	case s.Waiting != nil:
+		return ContainerState{
+			Status:  "waiting",
+			Reason:  s.Waiting.Reason,
+			Message: s.Waiting.Message,
+		}
+	case s.Terminated != nil:
+		exitCode := s.Terminated.ExitCode
+		return ContainerState{
+			Status:   "terminated",
+			Reason:   s.Terminated.Reason,
+			Message:  s.Terminated.Message,
+			ExitCode: &exitCode,
+		}
+	default:
+		return ContainerState{}
+	}
+}
+
+func formatCPU(list v1.ResourceList, name v1.ResourceName) string {
+	q, ok := list[name]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+func formatMemory(list v1.ResourceList, name v1.ResourceName) string {
+	q, ok := list[name]
+	if !ok {
+		return ""
 	}
+	return fmt.Sprintf("%dMi", q.Value()/(1024*1024))
+}
+
+func extendedResources(list v1.ResourceList) map[string]string {
+	var out map[string]string
+
+	for name, q := range list {
+		switch name {
+		case v1.ResourceCPU, v1.ResourceMemory, v1.ResourceEphemeralStorage:
+			continue
+		}
+
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[string(name)] = q.String()
+	}
+
+	return out
 }
@@ -32,7 +32,8 @@ func TestPodServiceStreamNamespaceLogs(t *testing.T) {
 			fake := &fakePodLogsStreamer{streamErr: tt.streamErr}
 			svc := &PodService{logsCollector: fake}
 
-			err := svc.StreamNamespaceLogs(context.Background(), "default", LogStreamOptions{}, func(LogStreamRecord) error {
+			scope := LogStreamScope{Namespaces: []string{"default"}}
+			err := svc.StreamNamespaceLogs(context.Background(), scope, LogStreamOptions{}, func(LogStreamRecord) error {
 				return nil
 			})
 
@@ -43,27 +44,44 @@ func TestPodServiceStreamNamespaceLogs(t *testing.T) {
 			}
 
 			require.NoError(t, err)
-			require.Equal(t, "default", fake.lastNamespace)
+			require.Equal(t, scope, fake.lastScope)
 		})
 	}
 }
 
+func TestPodServicePreviousLogs(t *testing.T) {
+	fake := &fakePodLogsStreamer{}
+	svc := &PodService{logsCollector: fake}
+
+	var got []LogStreamRecord
+	err := svc.PreviousLogs(context.Background(), "default", "api-0", "app", PreviousLogOptions{}, func(record LogStreamRecord) error {
+		got = append(got, record)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "default/api-0/app", fake.lastPrevious)
+	require.Len(t, got, 1)
+	require.True(t, got[0].Previous)
+}
+
 type fakePodLogsStreamer struct {
-	lastNamespace string
-	streamErr     error
+	lastScope    LogStreamScope
+	lastPrevious string
+	streamErr    error
 }
 
 func (f *fakePodLogsStreamer) StreamNamespace(
 	_ context.Context,
-	namespace string,
+	scope LogStreamScope,
 	_ LogStreamOptions,
 	onRecord func(LogStreamRecord) error,
 ) error {
-	f.lastNamespace = namespace
+	f.lastScope = scope
 
 	if onRecord != nil {
 		_ = onRecord(LogStreamRecord{
-			Namespace: namespace,
+			Namespace: "default",
 			Pod:       "api-0",
 			Message:   "line",
 			Timestamp: time.Now().UTC(),
@@ -72,3 +90,27 @@ func (f *fakePodLogsStreamer) StreamNamespace(
 
 	return f.streamErr
 }
+
+func (f *fakePodLogsStreamer) Previous(
+	_ context.Context,
+	namespace,
+	name,
+	container string,
+	_ PreviousLogOptions,
+	onRecord func(LogStreamRecord) error,
+) error {
+	f.lastPrevious = namespace + "/" + name + "/" + container
+
+	if onRecord != nil {
+		_ = onRecord(LogStreamRecord{
+			Namespace: namespace,
+			Pod:       name,
+			Container: container,
+			Previous:  true,
+			Message:   "crash",
+			Timestamp: time.Now().UTC(),
+		})
+	}
+
+	return f.streamErr
+}
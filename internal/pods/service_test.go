@@ -1,10 +1,23 @@
 package pods
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
 	"github.com/JNickson/cluster-telemetry-service/internal/testutil"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
 )
 
 // Golden File Tests
@@ -18,3 +31,104 @@ func TestMapPod(t *testing.T) {
 		},
 	)
 }
+
+type podWithMetrics struct {
+	Pod     v1.Pod                     `json:"pod"`
+	Metrics *metricsv1beta1.PodMetrics `json:"metrics"`
+}
+
+func TestMapPodWithUsage(t *testing.T) {
+	testutil.RunGoldenTest(
+		t,
+		"testdata/mapPodWithUsage",
+		func(input podWithMetrics) Pod {
+			return mapPodWithUsage(input.Pod, input.Metrics)
+		},
+	)
+}
+
+func TestBuildSnapshotWithoutPodMetrics(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(newTestPod("default", "api-0", "node-1", "api")))
+
+	metrics := metricsfake.NewSimpleClientset()
+	metrics.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("metrics API not available")
+	})
+
+	svc := NewPodService(newPodLister(indexer), nil, metrics)
+
+	out, err := svc.BuildSnapshot(context.Background())
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Nil(t, out[0].Usage)
+}
+
+func TestBuildSnapshotReusesPodMetrics(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := utils.Now
+	utils.Now = func() time.Time { return now }
+	defer func() { utils.Now = originalNow }()
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(newTestPod("default", "api-0", "node-1", "api")))
+
+	lists := 0
+	metrics := metricsfake.NewSimpleClientset()
+	metrics.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		lists++
+		return true, &metricsv1beta1.PodMetricsList{Items: []metricsv1beta1.PodMetrics{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-0"},
+			Containers: []metricsv1beta1.ContainerMetrics{{
+				Name: "api",
+				Usage: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("100m"),
+					v1.ResourceMemory: resource.MustParse("64Mi"),
+				},
+			}},
+		}}}, nil
+	})
+
+	svc := NewPodService(newPodLister(indexer), nil, metrics)
+
+	for range 3 {
+		out, err := svc.BuildSnapshot(context.Background())
+		require.NoError(t, err)
+		require.Len(t, out, 1)
+		require.NotNil(t, out[0].Usage)
+	}
+	require.Equal(t, 1, lists, "rebuilds within the TTL reuse the list")
+
+	now = now.Add(utils.DefaultCacheTTL)
+	_, err := svc.BuildSnapshot(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, lists, "an expired list is fetched again")
+}
+
+func TestBuildSnapshotAttachesWarnings(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, indexer.Add(newTestPod("default", "api-0", "node-1", "api")))
+	require.NoError(t, indexer.Add(newTestPod("default", "api-1", "node-1", "api")))
+
+	buf := events.NewBuffer(time.Hour, 100)
+	buf.Upsert(events.Event{
+		Namespace:      "default",
+		Name:           "api-0.backoff",
+		Type:           v1.EventTypeWarning,
+		Reason:         "BackOff",
+		Count:          3,
+		InvolvedObject: events.ObjectRef{Kind: "Pod", Namespace: "default", Name: "api-0"},
+		LastSeen:       time.Now(),
+	})
+
+	svc := NewPodService(newPodLister(indexer), nil, nil).WithEvents(buf)
+
+	out, err := svc.BuildSnapshot(context.Background())
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+
+	byName := map[string]Pod{out[0].Name: out[0], out[1].Name: out[1]}
+	require.Len(t, byName["api-0"].Warnings, 1)
+	require.Equal(t, "BackOff", byName["api-0"].Warnings[0].Reason)
+	require.Empty(t, byName["api-1"].Warnings)
+}
@@ -0,0 +1,153 @@
+package pods
+
+import (
+	"math"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// mapPodWithUsage maps p and attaches the usage from m to the pod and to
+// each container (including sidecars) that has a sample. A nil m leaves
+// usage unset.
+func mapPodWithUsage(p v1.Pod, m *metricsv1beta1.PodMetrics) Pod {
+	out := mapPod(p)
+	if m == nil {
+		return out
+	}
+
+	usageByContainer := make(map[string]v1.ResourceList, len(m.Containers))
+	for _, c := range m.Containers {
+		usageByContainer[c.Name] = c.Usage
+	}
+
+	var totals podUsageTotals
+
+	for _, spec := range p.Spec.Containers {
+		totals.add(spec, usageByContainer)
+	}
+	for _, spec := range p.Spec.InitContainers {
+		if isSidecar(spec) {
+			totals.add(spec, usageByContainer)
+		}
+	}
+
+	attachContainerUsage(out.Containers, p.Spec.Containers, usageByContainer)
+	attachContainerUsage(out.InitContainers, p.Spec.InitContainers, usageByContainer)
+
+	if totals.used != nil {
+		out.Usage = buildResourceUsage(totals.used, totals.requests, totals.limits())
+	}
+
+	return out
+}
+
+func attachContainerUsage(out []Container, specs []v1.Container, usageByContainer map[string]v1.ResourceList) {
+	specMap := make(map[string]v1.Container, len(specs))
+	for _, c := range specs {
+		specMap[c.Name] = c
+	}
+
+	for i := range out {
+		used, ok := usageByContainer[out[i].Name]
+		if !ok {
+			continue
+		}
+
+		spec := specMap[out[i].Name]
+		out[i].Usage = buildResourceUsage(used, spec.Resources.Requests, spec.Resources.Limits)
+	}
+}
+
+// podUsageTotals sums usage, requests and limits over the containers that
+// have a usage sample, so the pod ratios compare like with like.
+type podUsageTotals struct {
+	used     v1.ResourceList
+	requests v1.ResourceList
+	limited  v1.ResourceList
+
+	// unlimited records resources that at least one counted container has
+	// no limit for; the pod as a whole is then unbounded for that resource.
+	unlimited map[v1.ResourceName]bool
+}
+
+func (t *podUsageTotals) add(spec v1.Container, usageByContainer map[string]v1.ResourceList) {
+	usage, ok := usageByContainer[spec.Name]
+	if !ok {
+		return
+	}
+
+	t.used = addResources(t.used, usage)
+	t.requests = addResources(t.requests, spec.Resources.Requests)
+	t.limited = addResources(t.limited, spec.Resources.Limits)
+
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		if _, ok := spec.Resources.Limits[name]; !ok {
+			if t.unlimited == nil {
+				t.unlimited = make(map[v1.ResourceName]bool)
+			}
+			t.unlimited[name] = true
+		}
+	}
+}
+
+func (t *podUsageTotals) limits() v1.ResourceList {
+	out := make(v1.ResourceList, len(t.limited))
+	for name, q := range t.limited {
+		if !t.unlimited[name] {
+			out[name] = q
+		}
+	}
+	return out
+}
+
+func addResources(total, list v1.ResourceList) v1.ResourceList {
+	if total == nil {
+		total = v1.ResourceList{}
+	}
+
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		q, ok := list[name]
+		if !ok {
+			continue
+		}
+
+		sum := total[name]
+		sum.Add(q)
+		total[name] = sum
+	}
+
+	return total
+}
+
+func buildResourceUsage(used, requests, limits v1.ResourceList) *ResourceUsage {
+	cpuUsed := used[v1.ResourceCPU]
+	memUsed := used[v1.ResourceMemory]
+
+	return &ResourceUsage{
+		CPU:    formatCPU(used, v1.ResourceCPU),
+		Memory: formatMemory(used, v1.ResourceMemory),
+
+		CPURequestRatio:    usageRatio(cpuUsed, requests, v1.ResourceCPU),
+		CPULimitRatio:      usageRatio(cpuUsed, limits, v1.ResourceCPU),
+		MemoryRequestRatio: usageRatio(memUsed, requests, v1.ResourceMemory),
+		MemoryLimitRatio:   usageRatio(memUsed, limits, v1.ResourceMemory),
+	}
+}
+
+// usageRatio returns used divided by list[name], rounded to three decimal
+// places, or nil when list has no positive value for name.
+func usageRatio(used resource.Quantity, list v1.ResourceList, name v1.ResourceName) *float64 {
+	q, ok := list[name]
+	if !ok || q.Sign() <= 0 {
+		return nil
+	}
+
+	ratio := math.Round(used.AsApproximateFloat64()/q.AsApproximateFloat64()*1000) / 1000
+	return &ratio
+}
+
+func isSidecar(c v1.Container) bool {
+	return c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways
+}
@@ -4,63 +4,189 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
-	"os"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/JNickson/cluster-telemetry-service/internal/auth"
 	"github.com/JNickson/cluster-telemetry-service/internal/clients"
+	"github.com/JNickson/cluster-telemetry-service/internal/config"
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
 	"github.com/JNickson/cluster-telemetry-service/internal/handlers"
+	"github.com/JNickson/cluster-telemetry-service/internal/history"
 	"github.com/JNickson/cluster-telemetry-service/internal/informers"
 	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
 	"github.com/JNickson/cluster-telemetry-service/internal/pods"
 	"github.com/JNickson/cluster-telemetry-service/internal/store"
 	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 type App struct {
-	store        *store.Store
-	manager      *informers.Manager
-	nodesService nodes.Service
-	podsService  pods.Service
-	server       *http.Server
+	store            *store.Store
+	manager          *informers.Manager
+	nodesService     nodes.Service
+	podsService      pods.Service
+	workloadsService workloads.Service
+	server           *http.Server
+
+	refreshInterval time.Duration
+	refreshDebounce time.Duration
+	maxStaleness    time.Duration
+	shutdownTimeout time.Duration
+	logsStream      config.StreamConfig
+	nodeChanges     changeTrigger
+	podChanges      changeTrigger
+	workloadChanges changeTrigger
+
+	// podLister feeds the summary every pod, including the unscheduled and
+	// completed ones the pod snapshot leaves out.
+	podLister     corev1listers.PodLister
+	nodePoolLabel string
+	summaryMu     sync.Mutex
+
+	// compactInterval is zero when the store is in memory only.
+	compactInterval time.Duration
+
+	// authenticator is nil when the API is served without authentication,
+	// and authorizer is nil when authenticated callers may see everything.
+	authenticator auth.Authenticator
+	authorizer    auth.Authorizer
+
+	tls tlsSettings
 }
 
-func New(cfg *rest.Config) (*App, error) {
-	kubeClient, err := clients.NewKubeClient(cfg)
+// New builds the app from a validated configuration.
+func New(restCfg *rest.Config, cfg config.Config) (*App, error) {
+	kubeClient, err := clients.NewKubeClient(restCfg)
 	if err != nil {
 		return nil, err
 	}
 
-	metricsClient, err := clients.NewMetricsClient(cfg)
+	tlsSettings, err := newTLSSettings(cfg.TLS)
 	if err != nil {
 		return nil, err
 	}
 
-	st := store.New()
+	authenticator, err := newAuthenticator(kubeClient, cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizer := newAuthorizer(kubeClient, cfg.Auth, cfg.Authz, tlsSettings.clientCerts())
+
+	// Left nil when disabled so the services skip metrics.k8s.io entirely.
+	var metricsClient metricsclient.Interface
+	if cfg.Metrics.Enabled {
+		client, err := clients.NewMetricsClient(restCfg)
+		if err != nil {
+			return nil, err
+		}
+		metricsClient = client
+	} else {
+		slog.Info("metrics collection disabled")
+	}
+
+	st := store.New().WithHistory(history.New(cfg.History.Retention.Duration, cfg.History.Resolution.Duration))
+
+	compactInterval, err := attachStoreBackend(st, cfg.Store)
+	if err != nil {
+		return nil, err
+	}
 	manager := informers.NewManager(kubeClient)
 
 	factory := manager.Factory()
 	nodeLister := factory.Core().V1().Nodes().Lister()
 	podLister := factory.Core().V1().Pods().Lister()
 
-	nodeService := nodes.NewNodeService(nodeLister, podLister, metricsClient)
-	podsService := pods.NewPodService(podLister, kubeClient)
+	apps := factory.Apps().V1()
+	batch := factory.Batch().V1()
+	resolver := workloads.NewOwnerResolver(apps.ReplicaSets().Lister(), batch.Jobs().Lister())
+
+	nodeService := nodes.NewNodeService(nodeLister, podLister, metricsClient, resolver).WithEvents(st.Events())
+	podsService := pods.NewPodService(podLister, kubeClient, metricsClient).
+		WithEvents(st.Events()).
+		WithLogIntervals(cfg.Logs.RetryDelay.Duration, cfg.Logs.ReconcileInterval.Duration)
+
+	workloadsService := workloads.NewWorkloadService(
+		apps.Deployments().Lister(),
+		apps.StatefulSets().Lister(),
+		apps.DaemonSets().Lister(),
+		apps.ReplicaSets().Lister(),
+		batch.Jobs().Lister(),
+		batch.CronJobs().Lister(),
+		podLister,
+	)
+
+	app := &App{
+		store:            st,
+		manager:          manager,
+		nodesService:     nodeService,
+		podsService:      podsService,
+		workloadsService: workloadsService,
+		refreshInterval:  cfg.Refresh.Interval.Duration,
+		refreshDebounce:  cfg.Refresh.Debounce.Duration,
+		maxStaleness:     cfg.Refresh.ReadinessMaxStaleness.Duration,
+		shutdownTimeout:  cfg.Server.ShutdownTimeout.Duration,
+		logsStream:       cfg.Logs.Stream,
+		nodeChanges:      newChangeTrigger(),
+		podChanges:       newChangeTrigger(),
+		workloadChanges:  newChangeTrigger(),
+		podLister:        podLister,
+		nodePoolLabel:    cfg.Summary.NodePoolLabel,
+		compactInterval:  compactInterval,
+		authenticator:    authenticator,
+		authorizer:       authorizer,
+		tls:              tlsSettings,
+	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8001"
+	// Node snapshots embed the workloads scheduled on each node and
+	// workloads list the pods they own, so pod changes refresh every
+	// snapshot.
+	if err := manager.OnChange(factory.Core().V1().Nodes().Informer(), app.nodeChanges.Notify); err != nil {
+		return nil, err
+	}
+	if err := manager.OnChange(factory.Core().V1().Pods().Informer(), func() {
+		app.nodeChanges.Notify()
+		app.podChanges.Notify()
+		app.workloadChanges.Notify()
+	}); err != nil {
+		return nil, err
+	}
+	for _, informer := range []cache.SharedIndexInformer{
+		apps.Deployments().Informer(),
+		apps.StatefulSets().Informer(),
+		apps.DaemonSets().Informer(),
+		apps.ReplicaSets().Informer(),
+		batch.Jobs().Informer(),
+		batch.CronJobs().Informer(),
+	} {
+		if err := manager.OnChange(informer, app.workloadChanges.Notify); err != nil {
+			return nil, err
+		}
 	}
 
-	app := &App{store: st, manager: manager, nodesService: nodeService, podsService: podsService}
+	if err := manager.OnEvent(app.upsertEvent, app.removeEvent); err != nil {
+		return nil, err
+	}
 
 	server := &http.Server{
-		Addr:              ":" + port,
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
 		Handler:           app.setupRouter(),
 		ReadTimeout:       10 * time.Second,
 		WriteTimeout:      0,
 		IdleTimeout:       60 * time.Second,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
+	if tlsSettings.reloader != nil {
+		server.TLSConfig = tlsSettings.reloader.TLSConfig(tlsSettings.clientAuth)
+	}
 
 	app.server = server
 
@@ -69,61 +195,319 @@ func New(cfg *rest.Config) (*App, error) {
 
 func (a *App) Start(ctx context.Context) {
 
-	go a.manager.Start(ctx)
-
-	time.Sleep(1 * time.Second)
-
-	go a.startNodeReconciler(ctx)
-	go a.startPodReconciler(ctx)
-
+	// Serve straight away so /healthz answers during the initial sync;
+	// /readyz reports not ready until the first snapshots are stored.
 	go func() {
-		slog.Info("starting server", "addr", a.server.Addr)
-		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		slog.Info("starting server", "addr", a.server.Addr, "tls", a.tls.reloader != nil)
+
+		var err error
+		if a.tls.reloader != nil {
+			// The certificate comes from TLSConfig.GetCertificate.
+			err = a.server.ListenAndServeTLS("", "")
+		} else {
+			err = a.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			slog.Error("server failed", "error", err)
 		}
 	}()
 
+	if a.tls.reloader != nil {
+		go a.tls.reloader.Run(ctx, a.tls.reloadInterval)
+	}
+
+	if a.manager.Start(ctx) {
+		go a.startNodeReconciler(ctx)
+		go a.startPodReconciler(ctx)
+		go a.startWorkloadReconciler(ctx)
+	}
+
+	if a.compactInterval > 0 {
+		go a.runCompactor(ctx)
+	}
+
 	<-ctx.Done()
 
 	slog.Info("shutting down")
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
 	defer cancel()
 
 	_ = a.server.Shutdown(shutdownCtx)
 
+	if err := a.store.Close(); err != nil {
+		slog.Error("failed to save store state", "error", err)
+	}
+}
+
+// upsertEvent stores an event and, for Warning events about a pod or node,
+// schedules a refresh so the warning shows up in the snapshot.
+func (a *App) upsertEvent(e *corev1.Event) {
+	a.store.UpsertEvent(events.FromKube(e))
+
+	if e.Type != corev1.EventTypeWarning {
+		return
+	}
+	switch e.InvolvedObject.Kind {
+	case "Pod":
+		a.podChanges.Notify()
+	case "Node":
+		a.nodeChanges.Notify()
+	}
+}
+
+func (a *App) removeEvent(e *corev1.Event) {
+	a.store.DeleteEvent(e.Namespace, e.Name)
+}
+
+// statusForKubeError maps an API server error to the status returned to the
+// caller, so a missing pod or container is not reported as a server fault.
+func statusForKubeError(err error) int {
+	switch {
+	case apierrors.IsNotFound(err):
+		return http.StatusNotFound
+	case apierrors.IsBadRequest(err):
+		return http.StatusBadRequest
+	case apierrors.IsForbidden(err):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadGateway
+	}
 }
 
 func (a *App) setupRouter() http.Handler {
 	api := http.NewServeMux()
 
 	api.HandleFunc("/nodes", func(w http.ResponseWriter, r *http.Request) {
-		utils.WriteJSON(w, http.StatusOK, a.store.ListNodes())
+		query, err := nodeListQueryFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Nodes list the pods without a controller and the kube-system
+		// pods on them by name; those are shown where the caller can list
+		// pods.
+		access, err := a.namespaceAccess(r, listPodsAttributes)
+		if err != nil {
+			writeAuthorizationError(w, r, err)
+			return
+		}
+
+		all, meta := a.store.ListNodesWithMeta()
+		if writeSnapshotHeaders(w, r, meta) {
+			return
+		}
+
+		items, total, next := query.apply(all)
+		items = redactAll(items, access.redactNode)
+		if access.err != nil {
+			writeAuthorizationError(w, r, access.err)
+			return
+		}
+		writeList(w, items, total, next, meta, query.List)
 	})
 
 	api.HandleFunc("/pods", func(w http.ResponseWriter, r *http.Request) {
-		utils.WriteJSON(w, http.StatusOK, a.store.ListPods())
+		query, err := podListQueryFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		access, err := a.namespaceAccess(r, listPodsAttributes)
+		if err != nil {
+			writeAuthorizationError(w, r, err)
+			return
+		}
+		query.AllowNamespace = access.allowed
+
+		all, meta := a.store.ListPodsWithMeta()
+		if writeSnapshotHeaders(w, r, meta) {
+			return
+		}
+
+		items, total, next := query.apply(all)
+		if access.err != nil {
+			writeAuthorizationError(w, r, access.err)
+			return
+		}
+		writeList(w, items, total, next, meta, query.List)
 	})
 
-	api.HandleFunc("/pods/logs/stream", func(w http.ResponseWriter, r *http.Request) {
-		namespace := r.URL.Query().Get("namespace")
+	api.HandleFunc("/workloads", func(w http.ResponseWriter, r *http.Request) {
+		query, err := workloadListQueryFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Workloads list their pods by name; those are shown where the
+		// caller can list pods.
+		access, err := a.namespaceAccess(r, listPodsAttributes)
+		if err != nil {
+			writeAuthorizationError(w, r, err)
+			return
+		}
 
-		if namespace == "" {
-			http.Error(w, "namespace required", http.StatusBadRequest)
+		all, meta := a.store.ListWorkloadsWithMeta()
+		if writeSnapshotHeaders(w, r, meta) {
 			return
 		}
 
-		if r.URL.Query().Get("name") != "" {
-			http.Error(w, "name is not supported; stream is namespace-scoped", http.StatusBadRequest)
+		items, total, next := query.apply(all)
+		items = redactAll(items, access.redactWorkload)
+		if access.err != nil {
+			writeAuthorizationError(w, r, access.err)
 			return
 		}
+		writeList(w, items, total, next, meta, query.List)
+	})
 
-		opts, err := podLogsStreamOptionsFromQuery(r)
+	api.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		query, err := eventsQueryFromRequest(r)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
+		// Events are shown where the caller can list pods.
+		access, err := a.namespaceAccess(r, listPodsAttributes)
+		if err != nil {
+			writeAuthorizationError(w, r, err)
+			return
+		}
+		query.AllowNamespace = access.allowed
+
+		items, total := query.apply(a.store.Events())
+		if access.err != nil {
+			writeAuthorizationError(w, r, access.err)
+			return
+		}
+		w.Header().Set(totalCountHeader, strconv.Itoa(total))
+		utils.WriteJSON(w, http.StatusOK, items)
+	})
+
+	api.HandleFunc("/summary", func(w http.ResponseWriter, r *http.Request) {
+		// Namespace rollups are shown where the caller can list pods.
+		access, err := a.namespaceAccess(r, listPodsAttributes)
+		if err != nil {
+			writeAuthorizationError(w, r, err)
+			return
+		}
+
+		sum := a.store.Summary()
+		if sum.GeneratedAt.IsZero() {
+			http.Error(w, "summary not built yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		sum = access.redactSummary(sum)
+		if access.err != nil {
+			writeAuthorizationError(w, r, access.err)
+			return
+		}
+		utils.WriteJSON(w, http.StatusOK, sum)
+	})
+
+	api.HandleFunc("GET /nodes/{name}/history", func(w http.ResponseWriter, r *http.Request) {
+		query, err := historyQueryFromRequest(r, a.store.History())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Node samples hold readiness and usage only, as /nodes does
+		// without its workloads, so like /nodes they need no more than
+		// authentication.
+		name := r.PathValue("name")
+		samples, ok := a.store.History().Node(name, query.From, query.To, query.Step)
+		if !ok {
+			http.Error(w, "no history for node "+name, http.StatusNotFound)
+			return
+		}
+
+		utils.WriteJSON(w, http.StatusOK, newHistoryResponse("", name, query, samples))
+	})
+
+	api.HandleFunc("GET /pods/{namespace}/{name}/history", func(w http.ResponseWriter, r *http.Request) {
+		query, err := historyQueryFromRequest(r, a.store.History())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		namespace, name := r.PathValue("namespace"), r.PathValue("name")
+		attrs := listPodsAttributes
+		attrs.Namespace = namespace
+		if !a.authorize(w, r, attrs) {
+			return
+		}
+
+		samples, ok := a.store.History().Pod(namespace, name, query.From, query.To, query.Step)
+		if !ok {
+			http.Error(w, "no history for pod "+history.PodKey(namespace, name), http.StatusNotFound)
+			return
+		}
+
+		utils.WriteJSON(w, http.StatusOK, newHistoryResponse(namespace, name, query, samples))
+	})
+
+	api.HandleFunc("/nodes/watch", func(w http.ResponseWriter, r *http.Request) {
+		access, err := a.namespaceAccess(r, listPodsAttributes)
+		if err != nil {
+			writeAuthorizationError(w, r, err)
+			return
+		}
+
+		serveWatch(w, r, watchSource[nodes.Node]{
+			list:   a.store.ListNodesWithVersion,
+			since:  a.store.NodeEventsSince,
+			redact: access.redactNode,
+		})
+	})
+
+	api.HandleFunc("/pods/watch", func(w http.ResponseWriter, r *http.Request) {
+		access, err := a.namespaceAccess(r, listPodsAttributes)
+		if err != nil {
+			writeAuthorizationError(w, r, err)
+			return
+		}
+
+		serveWatch(w, r, watchSource[pods.Pod]{
+			list:  a.store.ListPodsWithVersion,
+			since: a.store.PodEventsSince,
+			allow: func(p pods.Pod) bool { return access.allowed(p.Namespace) },
+		})
+	})
+
+	api.HandleFunc("/pods/logs/stream", func(w http.ResponseWriter, r *http.Request) {
+		scope, err := podLogsStreamScopeFromQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		opts, err := podLogsStreamOptionsFromQuery(r, a.logsStream)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// All namespaces need cluster-wide access; otherwise every
+		// requested namespace must allow it before streaming starts.
+		namespaces := scope.Namespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{""}
+		}
+		for _, ns := range namespaces {
+			attrs := getPodLogsAttributes
+			attrs.Namespace = ns
+			if !a.authorize(w, r, attrs) {
+				return
+			}
+		}
+
 		flusher, ok := w.(http.Flusher)
 		if !ok {
 			http.Error(w, "streaming not supported", http.StatusInternalServerError)
@@ -146,6 +530,14 @@ func (a *App) setupRouter() http.Handler {
 		streamOpts := pods.LogStreamOptions{
 			FromStart: opts.FromStart,
 			TailLines: opts.TailLines,
+			Container: opts.Container,
+			Filter:    opts.Filter,
+
+			InitContainers:      opts.InitContainers,
+			EphemeralContainers: opts.EphemeralContainers,
+
+			PreviousOnRestart: opts.PreviousOnRestart,
+			PreviousTailLines: opts.PreviousTailLines,
 		}
 
 		handleRecord := func(record pods.LogStreamRecord) error {
@@ -172,17 +564,87 @@ func (a *App) setupRouter() http.Handler {
 			return nil
 		}
 
-		err = a.podsService.StreamNamespaceLogs(r.Context(), namespace, streamOpts, handleRecord)
+		err = a.podsService.StreamNamespaceLogs(r.Context(), scope, streamOpts, handleRecord)
 
 		if err != nil && r.Context().Err() == nil {
-			slog.Warn("pod logs stream ended with error", "namespace", namespace, "error", err)
+			slog.Warn("pod logs stream ended with error", "namespaces", scope.Namespaces, "user", requestUser(r), "error", err)
 		}
 	})
 
+	api.HandleFunc("/pods/logs/previous", func(w http.ResponseWriter, r *http.Request) {
+		opts, err := podPreviousLogsOptionsFromQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		attrs := getPodLogsAttributes
+		attrs.Namespace = opts.Namespace
+		attrs.Name = opts.Name
+		if !a.authorize(w, r, attrs) {
+			return
+		}
+
+		wroteHeader := false
+		writeHeader := func() {
+			if wroteHeader {
+				return
+			}
+			wroteHeader = true
+
+			if opts.Format == podLogsStreamFormatJSON {
+				w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+			} else {
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+
+		err = a.podsService.PreviousLogs(
+			r.Context(),
+			opts.Namespace,
+			opts.Name,
+			opts.Container,
+			pods.PreviousLogOptions{
+				TailLines:    opts.TailLines,
+				SinceSeconds: opts.SinceSeconds,
+				Filter:       opts.Filter,
+			},
+			func(record pods.LogStreamRecord) error {
+				writeHeader()
+				return writePodLogStreamRecord(w, record, opts.Format)
+			},
+		)
+
+		if err != nil && !wroteHeader {
+			http.Error(w, err.Error(), statusForKubeError(err))
+			return
+		}
+
+		if err != nil && r.Context().Err() == nil {
+			slog.Warn("previous pod logs ended with error", "namespace", opts.Namespace, "pod", opts.Name, "user", requestUser(r), "error", err)
+		}
+
+		writeHeader()
+	})
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", handlers.HealthHandler())
-	mux.HandleFunc("/readyz", handlers.ReadyHandler())
-	mux.Handle("/api/v1/", http.StripPrefix("/api/v1", api))
+	mux.HandleFunc("/readyz", handlers.ReadyHandler(a.readinessChecks()...))
+	// Health endpoints stay open for probes; everything else requires a
+	// token when authentication is enabled.
+	mux.Handle("/metrics", a.protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The pod series name every pod in every namespace.
+		if !a.authorize(w, r, listPodsAttributes) {
+			return
+		}
+
+		w.Header().Set("Content-Type", prometheusContentType)
+		if err := writePrometheusMetrics(w, a.store.ListNodes(), a.store.ListPods()); err != nil {
+			slog.Warn("failed to write metrics", "error", err)
+		}
+	})))
+	mux.Handle("/api/v1/", a.protect(http.StripPrefix("/api/v1", api)))
 
 	return mux
 }
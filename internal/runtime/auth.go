@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/auth"
+	"github.com/JNickson/cluster-telemetry-service/internal/config"
+	"k8s.io/client-go/kubernetes"
+)
+
+// newAuthenticator builds the authenticator selected by the auth mode. It
+// returns nil when authentication is disabled, which is the default.
+func newAuthenticator(kubeClient kubernetes.Interface, cfg config.AuthConfig) (auth.Authenticator, error) {
+	switch cfg.Mode {
+	case "", config.AuthModeNone:
+		return nil, nil
+
+	case config.AuthModeTokenReview:
+		slog.Info("authenticating requests with TokenReview", "audiences", cfg.Audiences, "cacheTTL", cfg.CacheTTL.Duration)
+		return auth.NewTokenReviewAuthenticator(kubeClient, cfg.Audiences, cfg.CacheTTL.Duration), nil
+
+	case config.AuthModeStatic:
+		if cfg.StaticToken == "" {
+			return nil, fmt.Errorf("static token required when auth mode is %s", config.AuthModeStatic)
+		}
+
+		slog.Warn("authenticating requests with a static token; use tokenreview outside local development")
+		return auth.NewStaticTokenAuthenticator(cfg.StaticToken), nil
+
+	default:
+		return nil, fmt.Errorf("invalid auth mode: %s", cfg.Mode)
+	}
+}
+
+// newAuthorizer returns the SubjectAccessReview authorizer when callers have
+// Kubernetes identities, from tokens reviewed by the API server or from
+// client certificates, and authorization is enabled. It returns nil
+// otherwise: a static token has no Kubernetes identity to check.
+func newAuthorizer(kubeClient kubernetes.Interface, authCfg config.AuthConfig, cfg config.AuthzConfig, clientCerts bool) auth.Authorizer {
+	tokenReview := authCfg.Mode == config.AuthModeTokenReview
+	certsOnly := (authCfg.Mode == "" || authCfg.Mode == config.AuthModeNone) && clientCerts
+	if !cfg.Enabled || (!tokenReview && !certsOnly) {
+		return nil
+	}
+
+	slog.Info("authorizing requests with SubjectAccessReview", "cacheTTL", cfg.CacheTTL.Duration)
+	return auth.NewSubjectAccessReviewAuthorizer(kubeClient, cfg.CacheTTL.Duration)
+}
+
+// protect wraps h with the authentication middleware when it is enabled.
+// Verified client certificates authenticate callers on their own when no
+// token authentication is configured; when they are optional, callers
+// without one are served anonymously.
+func (a *App) protect(h http.Handler) http.Handler {
+	switch {
+	case a.authenticator != nil:
+		return auth.Middleware(a.authenticator, h)
+	case a.tls.clientCerts() && a.tls.clientAuth == tls.VerifyClientCertIfGiven:
+		return auth.OptionalClientCertificateMiddleware(h)
+	case a.tls.clientCerts():
+		return auth.ClientCertificateMiddleware(h)
+	default:
+		return h
+	}
+}
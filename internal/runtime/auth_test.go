@@ -0,0 +1,115 @@
+package runtime
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/auth"
+	"github.com/JNickson/cluster-telemetry-service/internal/certs"
+	"github.com/JNickson/cluster-telemetry-service/internal/config"
+	"github.com/JNickson/cluster-telemetry-service/internal/store"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewAuthenticator(t *testing.T) {
+	client := fake.NewClientset()
+
+	a, err := newAuthenticator(client, config.AuthConfig{Mode: config.AuthModeNone})
+	require.NoError(t, err)
+	require.Nil(t, a)
+
+	a, err = newAuthenticator(client, config.AuthConfig{Mode: config.AuthModeTokenReview})
+	require.NoError(t, err)
+	require.IsType(t, &auth.TokenReviewAuthenticator{}, a)
+
+	_, err = newAuthenticator(client, config.AuthConfig{Mode: config.AuthModeStatic})
+	require.ErrorContains(t, err, "static token required")
+
+	a, err = newAuthenticator(client, config.AuthConfig{Mode: config.AuthModeStatic, StaticToken: "s3cret"})
+	require.NoError(t, err)
+	require.IsType(t, &auth.StaticTokenAuthenticator{}, a)
+
+	_, err = newAuthenticator(client, config.AuthConfig{Mode: "basic"})
+	require.ErrorContains(t, err, "invalid auth mode")
+}
+
+func TestNewAuthorizer(t *testing.T) {
+	client := fake.NewClientset()
+	enabled := config.AuthzConfig{Enabled: true}
+
+	a := newAuthorizer(client, config.AuthConfig{Mode: config.AuthModeStatic}, enabled, false)
+	require.Nil(t, a)
+
+	a = newAuthorizer(client, config.AuthConfig{Mode: config.AuthModeTokenReview}, enabled, false)
+	require.IsType(t, &auth.SubjectAccessReviewAuthorizer{}, a)
+
+	a = newAuthorizer(client, config.AuthConfig{Mode: config.AuthModeNone}, enabled, false)
+	require.Nil(t, a)
+
+	// Client certificates carry an identity of their own.
+	a = newAuthorizer(client, config.AuthConfig{Mode: config.AuthModeNone}, enabled, true)
+	require.IsType(t, &auth.SubjectAccessReviewAuthorizer{}, a)
+
+	a = newAuthorizer(client, config.AuthConfig{Mode: config.AuthModeTokenReview}, config.AuthzConfig{}, true)
+	require.Nil(t, a)
+}
+
+func TestRouterAuthentication(t *testing.T) {
+	app := &App{
+		store:         store.New(),
+		authenticator: auth.NewStaticTokenAuthenticator("s3cret"),
+	}
+	router := app.setupRouter()
+
+	tests := []struct {
+		name       string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{name: "health stays open", path: "/healthz", wantStatus: http.StatusOK},
+		{name: "api requires a token", path: "/api/v1/pods", wantStatus: http.StatusUnauthorized},
+		{name: "metrics require a token", path: "/metrics", wantStatus: http.StatusUnauthorized},
+		{name: "api with token", path: "/api/v1/pods", token: "s3cret", wantStatus: http.StatusOK},
+		{name: "metrics with token", path: "/metrics", token: "s3cret", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestRouterOptionalClientCertificates(t *testing.T) {
+	app := &App{
+		store: store.New(),
+		tls:   tlsSettings{reloader: &certs.Reloader{}, clientAuth: tls.VerifyClientCertIfGiven},
+	}
+	router := app.setupRouter()
+
+	req := httptest.NewRequest("GET", "/api/v1/pods", nil)
+	req.TLS = &tls.ConnectionState{}
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, "callers without a certificate are served anonymously")
+
+	app.tls.clientAuth = tls.RequireAndVerifyClientCert
+	rec = httptest.NewRecorder()
+	app.setupRouter().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
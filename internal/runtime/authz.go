@@ -0,0 +1,184 @@
+package runtime
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/auth"
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/summary"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
+)
+
+var (
+	listPodsAttributes   = auth.Attributes{Verb: "list", Resource: "pods"}
+	getPodLogsAttributes = auth.Attributes{Verb: "get", Resource: "pods", Subresource: "log"}
+)
+
+// authorize checks that the caller may perform attrs. When not, it writes
+// 403 (or 503 if the decision could not be made) and returns false. Without
+// an authorizer every request is allowed.
+func (a *App) authorize(w http.ResponseWriter, r *http.Request, attrs auth.Attributes) bool {
+	if a.authorizer == nil {
+		return true
+	}
+
+	u, ok := auth.UserFrom(r.Context())
+	if !ok {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
+	}
+
+	allowed, err := a.authorizer.Authorize(r.Context(), u, attrs)
+	if err != nil {
+		writeAuthorizationError(w, r, err)
+		return false
+	}
+	if !allowed {
+		http.Error(w, "forbidden: "+u.Name+" cannot "+attrs.String(), http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// namespaceAccess answers, per namespace, whether the caller may perform a
+// request such as listing pods. It is used to filter results rather than to
+// reject the request.
+type namespaceAccess struct {
+	ctx        context.Context
+	authorizer auth.Authorizer
+	user       *auth.User
+	attrs      auth.Attributes
+
+	all bool
+	err error
+}
+
+// namespaceAccess checks attrs across all namespaces first, so callers with
+// cluster-wide access need a single review. Per-namespace decisions are
+// made as namespaces are asked about and rely on the authorizer's cache.
+func (a *App) namespaceAccess(r *http.Request, attrs auth.Attributes) (*namespaceAccess, error) {
+	if a.authorizer == nil {
+		return &namespaceAccess{all: true}, nil
+	}
+
+	access := &namespaceAccess{ctx: r.Context(), authorizer: a.authorizer, attrs: attrs}
+
+	u, ok := auth.UserFrom(r.Context())
+	if !ok {
+		return access, nil
+	}
+	access.user = u
+
+	clusterWide := attrs
+	clusterWide.Namespace = ""
+	all, err := a.authorizer.Authorize(r.Context(), u, clusterWide)
+	if err != nil {
+		return nil, err
+	}
+	access.all = all
+
+	return access, nil
+}
+
+// allowed reports whether the caller may perform the request in namespace.
+// A failed review denies access and is kept in err.
+func (n *namespaceAccess) allowed(namespace string) bool {
+	if n.all {
+		return true
+	}
+	if n.user == nil {
+		return false
+	}
+
+	attrs := n.attrs
+	attrs.Namespace = namespace
+
+	allowed, err := n.authorizer.Authorize(n.ctx, n.user, attrs)
+	if err != nil {
+		if n.err == nil {
+			n.err = err
+		}
+		return false
+	}
+	return allowed
+}
+
+// redactNode removes the pods in namespaces the caller may not list pods in
+// from the node's workload breakdown. Workload names and pod counts are
+// kept; only pod names are hidden.
+func (n *namespaceAccess) redactNode(node nodes.Node) nodes.Node {
+	if n.all {
+		return node
+	}
+
+	bare := make([]string, 0, len(node.Workloads.BarePods))
+	for _, key := range node.Workloads.BarePods {
+		namespace, _, _ := strings.Cut(key, "/")
+		if n.allowed(namespace) {
+			bare = append(bare, key)
+		}
+	}
+	node.Workloads.BarePods = bare
+
+	if !n.allowed("kube-system") {
+		node.Workloads.System = []string{}
+	}
+
+	return node
+}
+
+// redactWorkload hides the pods of a workload in a namespace the caller may
+// not list pods in.
+func (n *namespaceAccess) redactWorkload(w workloads.Workload) workloads.Workload {
+	if !n.allowed(w.Namespace) {
+		w.Pods = []workloads.PodRef{}
+	}
+	return w
+}
+
+// redactSummary keeps the per-namespace rollups of the namespaces the caller
+// may list pods in. Cluster and node pool totals are kept.
+func (n *namespaceAccess) redactSummary(sum summary.Summary) summary.Summary {
+	if n.all {
+		return sum
+	}
+
+	namespaces := make([]summary.NamespaceSummary, 0, len(sum.Namespaces))
+	for _, ns := range sum.Namespaces {
+		if n.allowed(ns.Name) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sum.Namespaces = namespaces
+
+	return sum
+}
+
+func redactAll[T any](items []T, redact func(T) T) []T {
+	out := make([]T, len(items))
+	for i, item := range items {
+		out[i] = redact(item)
+	}
+	return out
+}
+
+func writeAuthorizationError(w http.ResponseWriter, r *http.Request, err error) {
+	slog.Error("failed to authorize request", "path", r.URL.Path, "user", requestUser(r), "error", err)
+	http.Error(w, "authorization unavailable", http.StatusServiceUnavailable)
+}
+
+// requestUser returns the name of the authenticated caller for logging: the
+// token's user or the client certificate's common name.
+func requestUser(r *http.Request) string {
+	if u, ok := auth.UserFrom(r.Context()); ok {
+		return u.Name
+	}
+	if u, ok := auth.ClientCertificateUser(r); ok {
+		return u.Name
+	}
+	return ""
+}
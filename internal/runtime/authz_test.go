@@ -0,0 +1,232 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/auth"
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/store"
+	"github.com/JNickson/cluster-telemetry-service/internal/summary"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
+	"github.com/stretchr/testify/require"
+)
+
+// staticAuthorizer allows the verb/resource pairs listed per namespace, with
+// "" meaning all namespaces.
+type staticAuthorizer struct {
+	allowed map[string][]string
+	err     error
+}
+
+func (s staticAuthorizer) Authorize(_ context.Context, _ *auth.User, attrs auth.Attributes) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+
+	for _, ns := range s.allowed[attrs.Verb+" "+attrs.Resource+"/"+attrs.Subresource] {
+		if ns == attrs.Namespace {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newAuthorizedTestApp(authorizer auth.Authorizer) *App {
+	st := store.New()
+	st.ReplacePods([]pods.Pod{
+		{Namespace: "team-a", Name: "api-0"},
+		{Namespace: "team-b", Name: "api-0"},
+		{Namespace: "kube-system", Name: "coredns"},
+	}, 0)
+	for _, ns := range []string{"team-a", "team-b"} {
+		st.UpsertEvent(events.Event{Namespace: ns, Name: "api-0.1", LastSeen: utils.Now()})
+	}
+
+	return &App{
+		store:         st,
+		authenticator: auth.NewStaticTokenAuthenticator("s3cret"),
+		authorizer:    authorizer,
+	}
+}
+
+func TestRouterAuthorization(t *testing.T) {
+	teamA := staticAuthorizer{allowed: map[string][]string{
+		"list pods/":   {"team-a"},
+		"get pods/log": {"team-a"},
+	}}
+	clusterWide := staticAuthorizer{allowed: map[string][]string{
+		"list pods/": {""},
+	}}
+
+	tests := []struct {
+		name           string
+		authorizer     auth.Authorizer
+		path           string
+		wantStatus     int
+		wantNamespaces []string
+	}{
+		{
+			name:           "pods filtered to allowed namespaces",
+			authorizer:     teamA,
+			path:           "/api/v1/pods",
+			wantStatus:     http.StatusOK,
+			wantNamespaces: []string{"team-a"},
+		},
+		{
+			name:           "cluster-wide access sees every pod",
+			authorizer:     clusterWide,
+			path:           "/api/v1/pods?sortBy=namespace",
+			wantStatus:     http.StatusOK,
+			wantNamespaces: []string{"kube-system", "team-a", "team-b"},
+		},
+		{
+			name:           "events filtered to allowed namespaces",
+			authorizer:     teamA,
+			path:           "/api/v1/events",
+			wantStatus:     http.StatusOK,
+			wantNamespaces: []string{"team-a"},
+		},
+		{
+			name:       "review failure",
+			authorizer: staticAuthorizer{err: errors.New("connection refused")},
+			path:       "/api/v1/pods",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "pod history in a forbidden namespace",
+			authorizer: teamA,
+			path:       "/api/v1/pods/team-b/api-0/history",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "log stream in a forbidden namespace",
+			authorizer: teamA,
+			path:       "/api/v1/pods/logs/stream?namespace=team-a,team-b",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "log stream across all namespaces",
+			authorizer: teamA,
+			path:       "/api/v1/pods/logs/stream?allNamespaces=true",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "metrics without cluster-wide access",
+			authorizer: teamA,
+			path:       "/metrics",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "metrics with cluster-wide access",
+			authorizer: clusterWide,
+			path:       "/metrics",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "previous logs in a forbidden namespace",
+			authorizer: teamA,
+			path:       "/api/v1/pods/logs/previous?namespace=team-b&name=api-0",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAuthorizedTestApp(tt.authorizer).setupRouter()
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("Authorization", "Bearer s3cret")
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantNamespaces == nil {
+				return
+			}
+
+			var items []struct {
+				Namespace string `json:"namespace"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &items))
+
+			namespaces := make([]string, 0, len(items))
+			for _, item := range items {
+				namespaces = append(namespaces, item.Namespace)
+			}
+			require.Equal(t, tt.wantNamespaces, namespaces)
+			require.Equal(t, "Authorization", rec.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestRouterRedactsPodNames(t *testing.T) {
+	teamA := staticAuthorizer{allowed: map[string][]string{
+		"list pods/": {"team-a"},
+	}}
+
+	app := newAuthorizedTestApp(teamA)
+	node := nodes.Node{Name: "node-a"}
+	node.Workloads.BarePods = []string{"team-a/debug", "team-b/debug"}
+	node.Workloads.System = []string{"coredns"}
+	app.store.ReplaceNodes([]nodes.Node{node}, 0)
+	app.store.ReplaceWorkloads([]workloads.Workload{
+		{Kind: workloads.KindDeployment, Namespace: "team-a", Name: "api", Pods: []workloads.PodRef{{Name: "api-0"}}},
+		{Kind: workloads.KindDeployment, Namespace: "team-b", Name: "api", Pods: []workloads.PodRef{{Name: "api-0"}}},
+	}, 0)
+	app.store.ReplaceSummary(summary.Summary{
+		GeneratedAt: utils.Now(),
+		Pods:        summary.PodCounts{Total: 3},
+		Namespaces: []summary.NamespaceSummary{
+			{Name: "kube-system"}, {Name: "team-a"}, {Name: "team-b"},
+		},
+	})
+	router := app.setupRouter()
+
+	get := func(path string, out any) {
+		t.Helper()
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+	}
+
+	var nodeList []nodes.Node
+	get("/api/v1/nodes", &nodeList)
+	require.Len(t, nodeList, 1)
+	require.Equal(t, []string{"team-a/debug"}, nodeList[0].Workloads.BarePods)
+	require.Empty(t, nodeList[0].Workloads.System)
+
+	var workloadList []workloads.Workload
+	get("/api/v1/workloads?sortBy=namespace", &workloadList)
+	require.Len(t, workloadList, 2)
+	require.Len(t, workloadList[0].Pods, 1)
+	require.Empty(t, workloadList[1].Pods)
+
+	var sum summary.Summary
+	get("/api/v1/summary", &sum)
+	require.Equal(t, 3, sum.Pods.Total, "cluster totals are kept")
+	require.Len(t, sum.Namespaces, 1)
+	require.Equal(t, "team-a", sum.Namespaces[0].Name)
+
+	// Node history has no pod names and is not filtered.
+	var history struct {
+		Samples []json.RawMessage `json:"samples"`
+	}
+	get("/api/v1/nodes/node-a/history", &history)
+	require.NotEmpty(t, history.Samples)
+
+	// The store keeps the full snapshot for other callers.
+	require.Len(t, app.store.ListNodes()[0].Workloads.BarePods, 2)
+	require.Len(t, app.store.Summary().Namespaces, 3)
+}
@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
+	v1 "k8s.io/api/core/v1"
+)
+
+type eventsQuery struct {
+	Filter events.Filter
+	Limit  int
+
+	// AllowNamespace, when set, hides events in namespaces the caller may
+	// not see.
+	AllowNamespace func(string) bool
+}
+
+func eventsQueryFromRequest(r *http.Request) (eventsQuery, error) {
+	q := r.URL.Query()
+
+	eventType := q.Get("type")
+	switch eventType {
+	case "", v1.EventTypeNormal, v1.EventTypeWarning:
+	default:
+		return eventsQuery{}, fmt.Errorf("invalid type: %s (expected %s or %s)", eventType, v1.EventTypeNormal, v1.EventTypeWarning)
+	}
+
+	var limit int
+	if raw := q.Get("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return eventsQuery{}, fmt.Errorf("invalid limit: %w", err)
+		}
+		if v < 1 || v > maxListLimit {
+			return eventsQuery{}, fmt.Errorf("limit must be between 1 and %d", maxListLimit)
+		}
+		limit = v
+	}
+
+	return eventsQuery{
+		Filter: events.Filter{
+			Namespace: q.Get("namespace"),
+			Kind:      q.Get("kind"),
+			Name:      q.Get("name"),
+			Type:      eventType,
+			Reason:    q.Get("reason"),
+		},
+		Limit: limit,
+	}, nil
+}
+
+// apply returns the newest Limit events and the number that matched.
+func (q eventsQuery) apply(buf *events.Buffer) ([]events.Event, int) {
+	items := buf.List(q.Filter)
+	if q.AllowNamespace != nil {
+		items = slices.DeleteFunc(items, func(e events.Event) bool {
+			return !q.AllowNamespace(e.Namespace)
+		})
+	}
+
+	total := len(items)
+	if q.Limit > 0 && len(items) > q.Limit {
+		items = items[:q.Limit]
+	}
+	return items, total
+}
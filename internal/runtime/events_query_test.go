@@ -0,0 +1,72 @@
+package runtime
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsQueryApply(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := utils.Now
+	utils.Now = func() time.Time { return now }
+	defer func() { utils.Now = originalNow }()
+
+	buf := events.NewBuffer(time.Hour, 100)
+	pod := events.ObjectRef{Kind: "Pod", Namespace: "default", Name: "api-0"}
+	buf.Upsert(events.Event{Namespace: "default", Name: "e1", Type: "Warning", Reason: "BackOff", InvolvedObject: pod, LastSeen: now.Add(-2 * time.Minute)})
+	buf.Upsert(events.Event{Namespace: "default", Name: "e2", Type: "Normal", Reason: "Pulled", InvolvedObject: pod, LastSeen: now.Add(-time.Minute)})
+	buf.Upsert(events.Event{Namespace: "jobs", Name: "e3", Type: "Warning", Reason: "FailedMount", InvolvedObject: events.ObjectRef{Kind: "Pod", Namespace: "jobs", Name: "worker-0"}, LastSeen: now})
+
+	tests := []struct {
+		name      string
+		url       string
+		wantNames []string
+		wantTotal int
+	}{
+		{name: "newest first", url: "/api/v1/events", wantNames: []string{"e3", "e2", "e1"}, wantTotal: 3},
+		{name: "filters by namespace", url: "/api/v1/events?namespace=default", wantNames: []string{"e2", "e1"}, wantTotal: 2},
+		{name: "filters by involved object", url: "/api/v1/events?kind=Pod&name=api-0&type=Warning", wantNames: []string{"e1"}, wantTotal: 1},
+		{name: "filters by reason", url: "/api/v1/events?reason=FailedMount", wantNames: []string{"e3"}, wantTotal: 1},
+		{name: "limits results", url: "/api/v1/events?limit=1", wantNames: []string{"e3"}, wantTotal: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := eventsQueryFromRequest(httptest.NewRequest("GET", tt.url, nil))
+			require.NoError(t, err)
+
+			items, total := query.apply(buf)
+
+			names := make([]string, 0, len(items))
+			for _, e := range items {
+				names = append(names, e.Name)
+			}
+			require.Equal(t, tt.wantNames, names)
+			require.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
+func TestEventsQueryFromRequestErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "invalid type", url: "/api/v1/events?type=Error", wantErr: "invalid type: Error"},
+		{name: "invalid limit", url: "/api/v1/events?limit=abc", wantErr: "invalid limit"},
+		{name: "limit out of range", url: "/api/v1/events?limit=0", wantErr: "limit must be between 1 and 1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := eventsQueryFromRequest(httptest.NewRequest("GET", tt.url, nil))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
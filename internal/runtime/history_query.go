@@ -0,0 +1,76 @@
+package runtime
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/history"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+)
+
+// historyQuery is the time range of a history request: the last Range up to
+// now, downsampled to one sample per Step.
+type historyQuery struct {
+	From time.Time
+	To   time.Time
+	Step time.Duration
+}
+
+type historyResponse[T any] struct {
+	Namespace   string    `json:"namespace,omitempty"`
+	Name        string    `json:"name"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	StepSeconds int64     `json:"stepSeconds"`
+	Samples     []T       `json:"samples"`
+}
+
+// historyQueryFromRequest reads range (default and maximum: the retention)
+// and step (default and minimum: the resolution) as Go durations.
+func historyQueryFromRequest(r *http.Request, h *history.History) (historyQuery, error) {
+	q := r.URL.Query()
+
+	window := h.Retention()
+	if raw := q.Get("range"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return historyQuery{}, fmt.Errorf("invalid range: %w", err)
+		}
+		if d <= 0 || d > h.Retention() {
+			return historyQuery{}, fmt.Errorf("range must be between 0s and %s", h.Retention())
+		}
+		window = d
+	}
+
+	step := h.Resolution()
+	if raw := q.Get("step"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return historyQuery{}, fmt.Errorf("invalid step: %w", err)
+		}
+		if d < h.Resolution() || d > window {
+			return historyQuery{}, fmt.Errorf("step must be between %s and %s", h.Resolution(), window)
+		}
+		step = d
+	}
+
+	now := utils.Now().UTC()
+
+	return historyQuery{
+		From: now.Add(-window),
+		To:   now,
+		Step: step,
+	}, nil
+}
+
+func newHistoryResponse[T any](namespace, name string, q historyQuery, samples []T) historyResponse[T] {
+	return historyResponse[T]{
+		Namespace:   namespace,
+		Name:        name,
+		From:        q.From,
+		To:          q.To,
+		StepSeconds: int64(q.Step / time.Second),
+		Samples:     samples,
+	}
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/history"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryQueryFromRequest(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := utils.Now
+	utils.Now = func() time.Time { return now }
+	defer func() { utils.Now = originalNow }()
+
+	h := history.New(time.Hour, 30*time.Second)
+
+	tests := []struct {
+		name     string
+		url      string
+		wantFrom time.Time
+		wantStep time.Duration
+		wantErr  string
+	}{
+		{name: "defaults to retention and resolution", url: "/history", wantFrom: now.Add(-time.Hour), wantStep: 30 * time.Second},
+		{name: "range and step", url: "/history?range=20m&step=1m", wantFrom: now.Add(-20 * time.Minute), wantStep: time.Minute},
+		{name: "range beyond retention", url: "/history?range=2h", wantErr: "range must be between 0s and 1h0m0s"},
+		{name: "invalid range", url: "/history?range=20", wantErr: "invalid range"},
+		{name: "step below resolution", url: "/history?step=10s", wantErr: "step must be between 30s and 1h0m0s"},
+		{name: "step larger than range", url: "/history?range=5m&step=10m", wantErr: "step must be between 30s and 5m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := historyQueryFromRequest(httptest.NewRequest("GET", tt.url, nil), h)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantFrom, q.From)
+			require.Equal(t, now, q.To)
+			require.Equal(t, tt.wantStep, q.Step)
+		})
+	}
+}
@@ -0,0 +1,505 @@
+package runtime
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/store"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	totalCountHeader    = "X-Total-Count"
+	continueTokenHeader = "X-Continue"
+
+	maxListLimit = 1000
+)
+
+var (
+	podSortFields      = []string{"name", "namespace", "node", "phase", "restarts", "age"}
+	nodeSortFields     = []string{"name", "age", "cpu", "memory"}
+	workloadSortFields = []string{"name", "namespace", "kind", "age"}
+)
+
+// listOptions holds the sorting and pagination parameters shared by the list
+// endpoints. After is decoded from the opaque continue token.
+type listOptions struct {
+	SortBy     string
+	Descending bool
+	Limit      int
+	After      *listKey
+
+	// Envelope wraps the items in an object with the snapshot metadata
+	// instead of returning a plain JSON array.
+	Envelope bool
+}
+
+type podListQuery struct {
+	Namespace string
+	Node      string
+	Phase     string
+	Ready     *bool
+	Selector  labels.Selector
+	List      listOptions
+
+	// AllowNamespace, when set, hides pods in namespaces the caller may not
+	// list.
+	AllowNamespace func(string) bool
+}
+
+type nodeListQuery struct {
+	Ready    *bool
+	Selector labels.Selector
+	List     listOptions
+}
+
+type workloadListQuery struct {
+	Namespace     string
+	Kind          string
+	RolloutStatus string
+	Selector      labels.Selector
+	List          listOptions
+}
+
+func podListQueryFromRequest(r *http.Request) (podListQuery, error) {
+	q := r.URL.Query()
+
+	ready, err := boolQueryParam(q, "ready")
+	if err != nil {
+		return podListQuery{}, err
+	}
+
+	selector, err := labelSelectorQueryParam(q)
+	if err != nil {
+		return podListQuery{}, err
+	}
+
+	list, err := listOptionsFromQuery(q, podSortFields)
+	if err != nil {
+		return podListQuery{}, err
+	}
+
+	return podListQuery{
+		Namespace: q.Get("namespace"),
+		Node:      q.Get("node"),
+		Phase:     q.Get("phase"),
+		Ready:     ready,
+		Selector:  selector,
+		List:      list,
+	}, nil
+}
+
+func nodeListQueryFromRequest(r *http.Request) (nodeListQuery, error) {
+	q := r.URL.Query()
+
+	ready, err := boolQueryParam(q, "ready")
+	if err != nil {
+		return nodeListQuery{}, err
+	}
+
+	selector, err := labelSelectorQueryParam(q)
+	if err != nil {
+		return nodeListQuery{}, err
+	}
+
+	list, err := listOptionsFromQuery(q, nodeSortFields)
+	if err != nil {
+		return nodeListQuery{}, err
+	}
+
+	return nodeListQuery{
+		Ready:    ready,
+		Selector: selector,
+		List:     list,
+	}, nil
+}
+
+func workloadListQueryFromRequest(r *http.Request) (workloadListQuery, error) {
+	q := r.URL.Query()
+
+	selector, err := labelSelectorQueryParam(q)
+	if err != nil {
+		return workloadListQuery{}, err
+	}
+
+	list, err := listOptionsFromQuery(q, workloadSortFields)
+	if err != nil {
+		return workloadListQuery{}, err
+	}
+
+	return workloadListQuery{
+		Namespace:     q.Get("namespace"),
+		Kind:          q.Get("kind"),
+		RolloutStatus: q.Get("rolloutStatus"),
+		Selector:      selector,
+		List:          list,
+	}, nil
+}
+
+func listOptionsFromQuery(q url.Values, sortFields []string) (listOptions, error) {
+	opts := listOptions{SortBy: "name"}
+
+	if raw := q.Get("sortBy"); raw != "" {
+		if !slices.Contains(sortFields, raw) {
+			return listOptions{}, fmt.Errorf("invalid sortBy: %s (expected one of %s)", raw, strings.Join(sortFields, ", "))
+		}
+		opts.SortBy = raw
+	}
+
+	switch raw := q.Get("order"); raw {
+	case "", "asc":
+	case "desc":
+		opts.Descending = true
+	default:
+		return listOptions{}, fmt.Errorf("invalid order: %s", raw)
+	}
+
+	if raw := q.Get("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return listOptions{}, fmt.Errorf("invalid limit: %w", err)
+		}
+		if v < 1 || v > maxListLimit {
+			return listOptions{}, fmt.Errorf("limit must be between 1 and %d", maxListLimit)
+		}
+		opts.Limit = v
+	}
+
+	if raw := q.Get("continue"); raw != "" {
+		token, err := decodeContinueToken(raw)
+		if err != nil {
+			return listOptions{}, err
+		}
+		if token.SortBy != opts.SortBy || token.Descending != opts.Descending {
+			return listOptions{}, fmt.Errorf("continue token does not match sortBy and order")
+		}
+		opts.After = &token.After
+	}
+
+	envelope, err := boolQueryParam(q, "envelope")
+	if err != nil {
+		return listOptions{}, err
+	}
+	opts.Envelope = envelope != nil && *envelope
+
+	return opts, nil
+}
+
+func boolQueryParam(q url.Values, key string) (*bool, error) {
+	raw := q.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return &v, nil
+}
+
+func labelSelectorQueryParam(q url.Values) (labels.Selector, error) {
+	raw := q.Get("labelSelector")
+	if raw == "" {
+		return labels.Everything(), nil
+	}
+
+	selector, err := labels.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid labelSelector: %w", err)
+	}
+
+	return selector, nil
+}
+
+func (q podListQuery) matches(p pods.Pod) bool {
+	if q.Namespace != "" && p.Namespace != q.Namespace {
+		return false
+	}
+	if q.Node != "" && p.Node != q.Node {
+		return false
+	}
+	if q.Phase != "" && !strings.EqualFold(p.Phase, q.Phase) {
+		return false
+	}
+	if q.Ready != nil && p.Ready != *q.Ready {
+		return false
+	}
+	if q.Selector != nil && !q.Selector.Matches(labels.Set(p.Labels)) {
+		return false
+	}
+	return q.AllowNamespace == nil || q.AllowNamespace(p.Namespace)
+}
+
+// apply filters, sorts and paginates items. It returns the page, the number
+// of items that matched the filters and the continue token for the next page.
+func (q podListQuery) apply(items []pods.Pod) ([]pods.Pod, int, string) {
+	out := make([]pods.Pod, 0, len(items))
+	for _, p := range items {
+		if q.matches(p) {
+			out = append(out, p)
+		}
+	}
+
+	page, next := paginate(out, q.List, func(p pods.Pod) listKey {
+		return podListKey(p, q.List.SortBy)
+	})
+	return page, len(out), next
+}
+
+func podListKey(p pods.Pod, field string) listKey {
+	key := listKey{ID: podKey(p)}
+	switch field {
+	case "namespace":
+		key.Str = p.Namespace
+	case "node":
+		key.Str = p.Node
+	case "phase":
+		key.Str = p.Phase
+	case "restarts":
+		key.Num = int64(p.Restarts)
+	case "age":
+		key.Num = ageSortValue(p.CreatedAt)
+	default:
+		key.Str = p.Name
+	}
+	return key
+}
+
+func podKey(p pods.Pod) string {
+	return p.Namespace + "/" + p.Name
+}
+
+func (q nodeListQuery) matches(n nodes.Node) bool {
+	if q.Ready != nil && n.Ready != *q.Ready {
+		return false
+	}
+	return q.Selector == nil || q.Selector.Matches(labels.Set(n.Labels))
+}
+
+func (q nodeListQuery) apply(items []nodes.Node) ([]nodes.Node, int, string) {
+	out := make([]nodes.Node, 0, len(items))
+	for _, n := range items {
+		if q.matches(n) {
+			out = append(out, n)
+		}
+	}
+
+	page, next := paginate(out, q.List, func(n nodes.Node) listKey {
+		return nodeListKey(n, q.List.SortBy)
+	})
+	return page, len(out), next
+}
+
+func nodeListKey(n nodes.Node, field string) listKey {
+	key := listKey{ID: n.Name}
+	switch field {
+	case "age":
+		key.Num = ageSortValue(n.CreatedAt)
+	case "cpu":
+		key.Num = quantitySortValue(n.CPU.Used)
+	case "memory":
+		key.Num = quantitySortValue(n.Memory.Used)
+	default:
+		key.Str = n.Name
+	}
+	return key
+}
+
+func (q workloadListQuery) matches(w workloads.Workload) bool {
+	if q.Namespace != "" && w.Namespace != q.Namespace {
+		return false
+	}
+	if q.Kind != "" && !strings.EqualFold(w.Kind, q.Kind) {
+		return false
+	}
+	if q.RolloutStatus != "" && !strings.EqualFold(w.RolloutStatus, q.RolloutStatus) {
+		return false
+	}
+	return q.Selector == nil || q.Selector.Matches(labels.Set(w.Labels))
+}
+
+func (q workloadListQuery) apply(items []workloads.Workload) ([]workloads.Workload, int, string) {
+	out := make([]workloads.Workload, 0, len(items))
+	for _, w := range items {
+		if q.matches(w) {
+			out = append(out, w)
+		}
+	}
+
+	page, next := paginate(out, q.List, func(w workloads.Workload) listKey {
+		return workloadListKey(w, q.List.SortBy)
+	})
+	return page, len(out), next
+}
+
+func workloadListKey(w workloads.Workload, field string) listKey {
+	key := listKey{ID: workloadKey(w)}
+	switch field {
+	case "namespace":
+		key.Str = w.Namespace
+	case "kind":
+		key.Str = w.Kind
+	case "age":
+		key.Num = ageSortValue(w.CreatedAt)
+	default:
+		key.Str = w.Name
+	}
+	return key
+}
+
+func workloadKey(w workloads.Workload) string {
+	return w.Namespace + "/" + w.Kind + "/" + w.Name
+}
+
+// listKey is an item's position in a sorted list. The sort field lands in
+// Num or Str, and ID, unique within the list, breaks ties so the order is
+// total and a continue token can name the exact item a page ended on.
+type listKey struct {
+	Num int64  `json:"n,omitempty"`
+	Str string `json:"s,omitempty"`
+	ID  string `json:"id"`
+}
+
+func compareListKeys(a, b listKey) int {
+	if c := cmp.Compare(a.Num, b.Num); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Str, b.Str); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+// ageSortValue orders younger objects, which have a smaller age, first.
+func ageSortValue(createdAt time.Time) int64 {
+	return -createdAt.UnixNano()
+}
+
+// quantitySortValue orders snapshot quantity strings; values that fail to
+// parse sort as zero.
+func quantitySortValue(raw string) int64 {
+	q, err := resource.ParseQuantity(raw)
+	if err != nil {
+		return 0
+	}
+	return q.MilliValue()
+}
+
+// paginate sorts items by their list key and returns the page that starts
+// after opts.After. Resuming from the last item's key rather than an offset
+// keeps pages from skipping or repeating items when the list changes
+// between requests.
+func paginate[T any](items []T, opts listOptions, keyOf func(T) listKey) ([]T, string) {
+	type entry struct {
+		item T
+		key  listKey
+	}
+
+	entries := make([]entry, len(items))
+	for i, item := range items {
+		entries[i] = entry{item: item, key: keyOf(item)}
+	}
+
+	compare := func(a, b listKey) int {
+		if opts.Descending {
+			return compareListKeys(b, a)
+		}
+		return compareListKeys(a, b)
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int {
+		return compare(a.key, b.key)
+	})
+
+	start := 0
+	if opts.After != nil {
+		start = sort.Search(len(entries), func(i int) bool {
+			return compare(entries[i].key, *opts.After) > 0
+		})
+	}
+	entries = entries[start:]
+
+	end := len(entries)
+	if opts.Limit > 0 && opts.Limit < end {
+		end = opts.Limit
+	}
+
+	page := make([]T, end)
+	for i := range page {
+		page[i] = entries[i].item
+	}
+
+	if end == len(entries) {
+		return page, ""
+	}
+
+	return page, encodeContinueToken(continueToken{
+		SortBy:     opts.SortBy,
+		Descending: opts.Descending,
+		After:      entries[end-1].key,
+	})
+}
+
+// continueToken is the decoded form of the opaque continue parameter. It
+// carries the sort it was issued for so a token cannot be replayed against a
+// different order.
+type continueToken struct {
+	SortBy     string  `json:"sortBy"`
+	Descending bool    `json:"desc,omitempty"`
+	After      listKey `json:"after"`
+}
+
+func encodeContinueToken(token continueToken) string {
+	raw, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeContinueToken(raw string) (continueToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return continueToken{}, fmt.Errorf("invalid continue token")
+	}
+
+	var token continueToken
+	if err := json.Unmarshal(data, &token); err != nil || token.SortBy == "" {
+		return continueToken{}, fmt.Errorf("invalid continue token")
+	}
+
+	return token, nil
+}
+
+// writeList writes a page of items with the total count and continue token
+// in response headers so the body stays a plain JSON array, unless the
+// envelope option asks for the items wrapped with the snapshot metadata.
+func writeList[T any](w http.ResponseWriter, items []T, total int, next string, meta store.SnapshotMeta, opts listOptions) {
+	w.Header().Set(totalCountHeader, strconv.Itoa(total))
+	if next != "" {
+		w.Header().Set(continueTokenHeader, next)
+	}
+
+	if opts.Envelope {
+		utils.WriteJSON(w, http.StatusOK, listEnvelope[T]{
+			Metadata: newListMetadata(meta, total, next),
+			Items:    items,
+		})
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, items)
+}
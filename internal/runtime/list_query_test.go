@@ -0,0 +1,281 @@
+package runtime
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPodListQueryApply(t *testing.T) {
+	base := time.Date(2026, time.February, 19, 12, 0, 0, 0, time.UTC)
+
+	items := []pods.Pod{
+		{Name: "api-0", Namespace: "default", Node: "node-a", Phase: "Running", Ready: true, Restarts: 2, CreatedAt: base, Labels: map[string]string{"app": "api"}},
+		{Name: "api-1", Namespace: "default", Node: "node-b", Phase: "Running", Ready: false, Restarts: 7, CreatedAt: base.Add(time.Hour), Labels: map[string]string{"app": "api"}},
+		{Name: "worker-0", Namespace: "jobs", Node: "node-a", Phase: "Pending", Ready: false, Restarts: 0, CreatedAt: base.Add(2 * time.Hour), Labels: map[string]string{"app": "worker"}},
+		{Name: "db-0", Namespace: "default", Node: "node-b", Phase: "Running", Ready: true, Restarts: 1, CreatedAt: base.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		wantNames []string
+		wantTotal int
+		wantNext  bool
+	}{
+		{
+			name:      "defaults sort by name",
+			url:       "/api/v1/pods",
+			wantNames: []string{"api-0", "api-1", "db-0", "worker-0"},
+			wantTotal: 4,
+		},
+		{
+			name:      "filters by namespace and node",
+			url:       "/api/v1/pods?namespace=default&node=node-b",
+			wantNames: []string{"api-1", "db-0"},
+			wantTotal: 2,
+		},
+		{
+			name:      "filters by phase and ready",
+			url:       "/api/v1/pods?phase=running&ready=true",
+			wantNames: []string{"api-0", "db-0"},
+			wantTotal: 2,
+		},
+		{
+			name:      "filters by label selector",
+			url:       "/api/v1/pods?labelSelector=app%20in%20(api)",
+			wantNames: []string{"api-0", "api-1"},
+			wantTotal: 2,
+		},
+		{
+			name:      "sorts by restarts descending",
+			url:       "/api/v1/pods?sortBy=restarts&order=desc",
+			wantNames: []string{"api-1", "api-0", "db-0", "worker-0"},
+			wantTotal: 4,
+		},
+		{
+			name:      "sorts by age with youngest first",
+			url:       "/api/v1/pods?sortBy=age",
+			wantNames: []string{"worker-0", "api-1", "api-0", "db-0"},
+			wantTotal: 4,
+		},
+		{
+			name:      "limits results and returns continue token",
+			url:       "/api/v1/pods?limit=3",
+			wantNames: []string{"api-0", "api-1", "db-0"},
+			wantTotal: 4,
+			wantNext:  true,
+		},
+		{
+			name:      "continues from token",
+			url:       "/api/v1/pods?limit=3&continue=" + encodeContinueToken(continueToken{SortBy: "name", After: listKey{Str: "db-0", ID: "default/db-0"}}),
+			wantNames: []string{"worker-0"},
+			wantTotal: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := podListQueryFromRequest(httptest.NewRequest("GET", tt.url, nil))
+			require.NoError(t, err)
+
+			page, total, next := query.apply(items)
+
+			names := make([]string, 0, len(page))
+			for _, p := range page {
+				names = append(names, p.Name)
+			}
+
+			require.Equal(t, tt.wantNames, names)
+			require.Equal(t, tt.wantTotal, total)
+			require.Equal(t, tt.wantNext, next != "")
+		})
+	}
+}
+
+func TestPodListQueryFromRequestErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		rawQuery    string
+		errContains string
+	}{
+		{name: "invalid ready", rawQuery: "ready=maybe", errContains: "invalid ready"},
+		{name: "invalid selector", rawQuery: "labelSelector=app%20in%20(", errContains: "invalid labelSelector"},
+		{name: "invalid sort field", rawQuery: "sortBy=cpu", errContains: "invalid sortBy"},
+		{name: "invalid order", rawQuery: "order=up", errContains: "invalid order"},
+		{name: "limit too high", rawQuery: "limit=5000", errContains: "limit must be between"},
+		{name: "invalid continue", rawQuery: "continue=not-a-token!", errContains: "invalid continue token"},
+		{name: "continue for another sort", rawQuery: "sortBy=age&continue=" + encodeContinueToken(continueToken{SortBy: "name"}), errContains: "does not match sortBy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/pods", nil)
+			req.URL.RawQuery = tt.rawQuery
+
+			_, err := podListQueryFromRequest(req)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestNodeListQueryApply(t *testing.T) {
+	items := []nodes.Node{
+		{Name: "node-a", Ready: true, CPU: nodes.Usage{Used: "1500m"}, Memory: nodes.Usage{Used: "512Mi"}, Labels: map[string]string{"pool": "general"}},
+		{Name: "node-b", Ready: true, CPU: nodes.Usage{Used: "250m"}, Memory: nodes.Usage{Used: "2048Mi"}, Labels: map[string]string{"pool": "gpu"}},
+		{Name: "node-c", Ready: false, CPU: nodes.Usage{Used: "2"}, Memory: nodes.Usage{Used: "1Gi"}, Labels: map[string]string{"pool": "general"}},
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		wantNames []string
+		wantTotal int
+	}{
+		{
+			name:      "filters by node labels",
+			url:       "/api/v1/nodes?labelSelector=pool%3Dgeneral",
+			wantNames: []string{"node-a", "node-c"},
+			wantTotal: 2,
+		},
+		{
+			name:      "filters by ready",
+			url:       "/api/v1/nodes?ready=false",
+			wantNames: []string{"node-c"},
+			wantTotal: 1,
+		},
+		{
+			name:      "sorts by cpu usage descending",
+			url:       "/api/v1/nodes?sortBy=cpu&order=desc",
+			wantNames: []string{"node-c", "node-a", "node-b"},
+			wantTotal: 3,
+		},
+		{
+			name:      "sorts by memory usage",
+			url:       "/api/v1/nodes?sortBy=memory&limit=2",
+			wantNames: []string{"node-a", "node-c"},
+			wantTotal: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := nodeListQueryFromRequest(httptest.NewRequest("GET", tt.url, nil))
+			require.NoError(t, err)
+
+			page, total, _ := query.apply(items)
+
+			names := make([]string, 0, len(page))
+			for _, n := range page {
+				names = append(names, n.Name)
+			}
+
+			require.Equal(t, tt.wantNames, names)
+			require.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
+func TestWorkloadListQueryApply(t *testing.T) {
+	base := time.Date(2026, time.February, 19, 12, 0, 0, 0, time.UTC)
+
+	items := []workloads.Workload{
+		{Kind: "Deployment", Namespace: "default", Name: "api", RolloutStatus: "Complete", CreatedAt: base, Labels: map[string]string{"team": "core"}},
+		{Kind: "Deployment", Namespace: "default", Name: "web", RolloutStatus: "Progressing", CreatedAt: base.Add(time.Hour)},
+		{Kind: "StatefulSet", Namespace: "data", Name: "db", RolloutStatus: "Complete", CreatedAt: base.Add(-time.Hour), Labels: map[string]string{"team": "core"}},
+		{Kind: "CronJob", Namespace: "ops", Name: "backup", RolloutStatus: "Scheduled", CreatedAt: base.Add(2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		wantNames []string
+		wantTotal int
+	}{
+		{
+			name:      "defaults sort by name",
+			url:       "/api/v1/workloads",
+			wantNames: []string{"api", "backup", "db", "web"},
+			wantTotal: 4,
+		},
+		{
+			name:      "filters by kind and namespace",
+			url:       "/api/v1/workloads?kind=deployment&namespace=default",
+			wantNames: []string{"api", "web"},
+			wantTotal: 2,
+		},
+		{
+			name:      "filters by rollout status",
+			url:       "/api/v1/workloads?rolloutStatus=Progressing",
+			wantNames: []string{"web"},
+			wantTotal: 1,
+		},
+		{
+			name:      "filters by label selector",
+			url:       "/api/v1/workloads?labelSelector=team%3Dcore",
+			wantNames: []string{"api", "db"},
+			wantTotal: 2,
+		},
+		{
+			name:      "sorts by age",
+			url:       "/api/v1/workloads?sortBy=age&limit=2",
+			wantNames: []string{"backup", "web"},
+			wantTotal: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := workloadListQueryFromRequest(httptest.NewRequest("GET", tt.url, nil))
+			require.NoError(t, err)
+
+			page, total, _ := query.apply(items)
+
+			names := make([]string, 0, len(page))
+			for _, w := range page {
+				names = append(names, w.Name)
+			}
+
+			require.Equal(t, tt.wantNames, names)
+			require.Equal(t, tt.wantTotal, total)
+		})
+	}
+}
+
+func TestPaginatePastEnd(t *testing.T) {
+	page, next := paginate([]int{1, 2, 3}, listOptions{After: &listKey{Num: 5}, Limit: 2}, intListKey)
+	require.Empty(t, page)
+	require.Empty(t, next)
+}
+
+func TestPaginateResumesAfterListChanges(t *testing.T) {
+	opts := listOptions{SortBy: "name", Limit: 2}
+
+	page, next := paginate([]int{1, 2, 3, 4, 5}, opts, intListKey)
+	require.Equal(t, []int{1, 2}, page)
+	require.NotEmpty(t, next)
+
+	token, err := decodeContinueToken(next)
+	require.NoError(t, err)
+	opts.After = &token.After
+
+	// An item before the cursor disappears and one is added after it; the
+	// next page neither repeats nor skips anything.
+	page, _ = paginate([]int{2, 3, 4, 5, 6}, opts, intListKey)
+	require.Equal(t, []int{3, 4}, page)
+
+	opts.Descending = true
+	opts.After = &listKey{Num: 4}
+	page, _ = paginate([]int{1, 2, 3, 4, 5}, opts, intListKey)
+	require.Equal(t, []int{3, 2}, page)
+}
+
+func intListKey(v int) listKey {
+	return listKey{Num: int64(v)}
+}
@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/JNickson/cluster-telemetry-service/internal/config"
 	"github.com/JNickson/cluster-telemetry-service/internal/pods"
 )
 
@@ -23,28 +28,45 @@ type podLogsStreamOptions struct {
 	Frequency time.Duration
 	FromStart bool
 	TailLines *int64
+	Container string
+	Filter    pods.LogFilter
+
+	InitContainers      bool
+	EphemeralContainers bool
+
+	PreviousOnRestart bool
+	PreviousTailLines int64
+}
+
+// podPreviousLogsOptions are the query options of the previous (terminated)
+// container logs endpoint.
+type podPreviousLogsOptions struct {
+	Namespace    string
+	Name         string
+	Container    string
+	Format       podLogsStreamFormat
+	TailLines    *int64
+	SinceSeconds *int64
+	Filter       pods.LogFilter
 }
 
 const (
-	defaultPodLogsStreamFrequency = 500 * time.Millisecond
-	minPodLogsStreamFrequency     = 100 * time.Millisecond
-	maxPodLogsStreamFrequency     = 10 * time.Second
+	defaultPodLogsPreviousTailLines = 50
+
+	maxPodLogsFilterLength = 1024
 )
 
-func podLogsStreamOptionsFromQuery(r *http.Request) (podLogsStreamOptions, error) {
+// podLogsStreamOptionsFromQuery parses the stream options; frequencyMs is
+// bounded by the configured stream frequencies.
+func podLogsStreamOptionsFromQuery(r *http.Request, bounds config.StreamConfig) (podLogsStreamOptions, error) {
 	q := r.URL.Query()
 
-	format := podLogsStreamFormatJSON
-	if raw := q.Get("format"); raw != "" {
-		switch podLogsStreamFormat(raw) {
-		case podLogsStreamFormatText, podLogsStreamFormatJSON:
-			format = podLogsStreamFormat(raw)
-		default:
-			return podLogsStreamOptions{}, fmt.Errorf("invalid format: %s", raw)
-		}
+	format, err := podLogsFormatFromQuery(q)
+	if err != nil {
+		return podLogsStreamOptions{}, err
 	}
 
-	frequency := defaultPodLogsStreamFrequency
+	frequency := bounds.DefaultFrequency.Duration
 	if raw := q.Get("frequencyMs"); raw != "" {
 		ms, err := strconv.Atoi(raw)
 		if err != nil {
@@ -52,34 +74,52 @@ func podLogsStreamOptionsFromQuery(r *http.Request) (podLogsStreamOptions, error
 		}
 
 		frequency = time.Duration(ms) * time.Millisecond
-		if frequency < minPodLogsStreamFrequency || frequency > maxPodLogsStreamFrequency {
+		if frequency < bounds.MinFrequency.Duration || frequency > bounds.MaxFrequency.Duration {
 			return podLogsStreamOptions{}, fmt.Errorf(
 				"frequencyMs must be between %d and %d",
-				minPodLogsStreamFrequency/time.Millisecond,
-				maxPodLogsStreamFrequency/time.Millisecond,
+				bounds.MinFrequency.Milliseconds(),
+				bounds.MaxFrequency.Milliseconds(),
 			)
 		}
 	}
 
-	fromStart := false
-	if raw := q.Get("fromStart"); raw != "" {
-		v, err := strconv.ParseBool(raw)
-		if err != nil {
-			return podLogsStreamOptions{}, fmt.Errorf("invalid fromStart: %w", err)
-		}
-		fromStart = v
+	fromStart, err := podLogsBoolFromQuery(q, "fromStart")
+	if err != nil {
+		return podLogsStreamOptions{}, err
 	}
 
-	var tailLines *int64
-	if raw := q.Get("tailLines"); raw != "" {
-		v, err := strconv.ParseInt(raw, 10, 64)
-		if err != nil {
-			return podLogsStreamOptions{}, fmt.Errorf("invalid tailLines: %w", err)
-		}
-		if v < 1 {
-			return podLogsStreamOptions{}, fmt.Errorf("tailLines must be >= 1")
-		}
-		tailLines = &v
+	initContainers, err := podLogsBoolFromQuery(q, "initContainers")
+	if err != nil {
+		return podLogsStreamOptions{}, err
+	}
+
+	ephemeralContainers, err := podLogsBoolFromQuery(q, "ephemeralContainers")
+	if err != nil {
+		return podLogsStreamOptions{}, err
+	}
+
+	tailLines, err := podLogsPositiveIntFromQuery(q, "tailLines")
+	if err != nil {
+		return podLogsStreamOptions{}, err
+	}
+
+	previousOnRestart, err := podLogsBoolFromQuery(q, "previousOnRestart")
+	if err != nil {
+		return podLogsStreamOptions{}, err
+	}
+
+	var previousTailLines int64
+	if v, err := podLogsPositiveIntFromQuery(q, "previousTailLines"); err != nil {
+		return podLogsStreamOptions{}, err
+	} else if v != nil {
+		previousTailLines = *v
+	} else if previousOnRestart {
+		previousTailLines = defaultPodLogsPreviousTailLines
+	}
+
+	filter, err := podLogsFilterFromQuery(q)
+	if err != nil {
+		return podLogsStreamOptions{}, err
 	}
 
 	return podLogsStreamOptions{
@@ -87,9 +127,179 @@ func podLogsStreamOptionsFromQuery(r *http.Request) (podLogsStreamOptions, error
 		Frequency: frequency,
 		FromStart: fromStart,
 		TailLines: tailLines,
+		Container: q.Get("container"),
+		Filter:    filter,
+
+		InitContainers:      initContainers,
+		EphemeralContainers: ephemeralContainers,
+
+		PreviousOnRestart: previousOnRestart,
+		PreviousTailLines: previousTailLines,
+	}, nil
+}
+
+func podPreviousLogsOptionsFromQuery(r *http.Request) (podPreviousLogsOptions, error) {
+	q := r.URL.Query()
+
+	namespace := q.Get("namespace")
+	if namespace == "" {
+		return podPreviousLogsOptions{}, fmt.Errorf("namespace required")
+	}
+
+	name := q.Get("name")
+	if name == "" {
+		return podPreviousLogsOptions{}, fmt.Errorf("name required")
+	}
+
+	format, err := podLogsFormatFromQuery(q)
+	if err != nil {
+		return podPreviousLogsOptions{}, err
+	}
+
+	tailLines, err := podLogsPositiveIntFromQuery(q, "tailLines")
+	if err != nil {
+		return podPreviousLogsOptions{}, err
+	}
+
+	sinceSeconds, err := podLogsPositiveIntFromQuery(q, "sinceSeconds")
+	if err != nil {
+		return podPreviousLogsOptions{}, err
+	}
+
+	filter, err := podLogsFilterFromQuery(q)
+	if err != nil {
+		return podPreviousLogsOptions{}, err
+	}
+
+	return podPreviousLogsOptions{
+		Namespace:    namespace,
+		Name:         name,
+		Container:    q.Get("container"),
+		Format:       format,
+		TailLines:    tailLines,
+		SinceSeconds: sinceSeconds,
+		Filter:       filter,
+	}, nil
+}
+
+func podLogsFormatFromQuery(q url.Values) (podLogsStreamFormat, error) {
+	raw := q.Get("format")
+	if raw == "" {
+		return podLogsStreamFormatJSON, nil
+	}
+
+	switch podLogsStreamFormat(raw) {
+	case podLogsStreamFormatText, podLogsStreamFormatJSON:
+		return podLogsStreamFormat(raw), nil
+	default:
+		return "", fmt.Errorf("invalid format: %s", raw)
+	}
+}
+
+func podLogsPositiveIntFromQuery(q url.Values, key string) (*int64, error) {
+	raw := q.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if v < 1 {
+		return nil, fmt.Errorf("%s must be >= 1", key)
+	}
+
+	return &v, nil
+}
+
+func podLogsBoolFromQuery(q url.Values, key string) (bool, error) {
+	raw := q.Get(key)
+	if raw == "" {
+		return false, nil
+	}
+
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return v, nil
+}
+
+// podLogsStreamScopeFromQuery reads the pods to stream: a comma-separated
+// namespace list or allNamespaces=true, an optional labelSelector and an
+// optional comma-separated list of pod names.
+func podLogsStreamScopeFromQuery(r *http.Request) (pods.LogStreamScope, error) {
+	q := r.URL.Query()
+
+	allNamespaces, err := podLogsBoolFromQuery(q, "allNamespaces")
+	if err != nil {
+		return pods.LogStreamScope{}, err
+	}
+
+	namespaces := splitCommaList(q.Get("namespace"))
+
+	switch {
+	case allNamespaces && len(namespaces) > 0:
+		return pods.LogStreamScope{}, fmt.Errorf("namespace and allNamespaces are mutually exclusive")
+	case !allNamespaces && len(namespaces) == 0:
+		return pods.LogStreamScope{}, fmt.Errorf("namespace required")
+	}
+
+	selector, err := labelSelectorQueryParam(q)
+	if err != nil {
+		return pods.LogStreamScope{}, err
+	}
+
+	return pods.LogStreamScope{
+		Namespaces: namespaces,
+		Selector:   selector,
+		Names:      splitCommaList(q.Get("name")),
 	}, nil
 }
 
+func splitCommaList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" && !slices.Contains(out, part) {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func podLogsFilterFromQuery(q url.Values) (pods.LogFilter, error) {
+	var filter pods.LogFilter
+
+	for _, key := range []string{"include", "exclude", "contains", "notContains"} {
+		if len(q.Get(key)) > maxPodLogsFilterLength {
+			return pods.LogFilter{}, fmt.Errorf("%s must be at most %d characters", key, maxPodLogsFilterLength)
+		}
+	}
+
+	if raw := q.Get("include"); raw != "" {
+		re, err := regexp.Compile(raw)
+		if err != nil {
+			return pods.LogFilter{}, fmt.Errorf("invalid include: %w", err)
+		}
+		filter.Include = re
+	}
+
+	if raw := q.Get("exclude"); raw != "" {
+		re, err := regexp.Compile(raw)
+		if err != nil {
+			return pods.LogFilter{}, fmt.Errorf("invalid exclude: %w", err)
+		}
+		filter.Exclude = re
+	}
+
+	filter.Contains = q.Get("contains")
+	filter.NotContains = q.Get("notContains")
+
+	return filter, nil
+}
+
 func writePodLogStreamRecord(w io.Writer, record pods.LogStreamRecord, format podLogsStreamFormat) error {
 	switch format {
 	case podLogsStreamFormatJSON:
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/JNickson/cluster-telemetry-service/internal/config"
 	"github.com/JNickson/cluster-telemetry-service/internal/pods"
 )
 
@@ -18,7 +19,7 @@ func BenchmarkPodLogsStreamOptionsFromQuery(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		if _, err := podLogsStreamOptionsFromQuery(req); err != nil {
+		if _, err := podLogsStreamOptionsFromQuery(req, config.Default().Logs.Stream); err != nil {
 			b.Fatalf("unexpected parse error: %v", err)
 		}
 	}
@@ -28,6 +29,7 @@ func BenchmarkWritePodLogStreamRecordJSON(b *testing.B) {
 	record := pods.LogStreamRecord{
 		Namespace: "default",
 		Pod:       "api-0",
+		Container: "app",
 		Message:   "hello benchmark",
 		Timestamp: time.Date(2026, time.February, 19, 12, 0, 0, 0, time.UTC),
 	}
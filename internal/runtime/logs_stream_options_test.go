@@ -2,11 +2,14 @@ package runtime
 
 import (
 	"net/http/httptest"
+	"regexp"
 	"testing"
 	"time"
 
+	"github.com/JNickson/cluster-telemetry-service/internal/config"
 	"github.com/JNickson/cluster-telemetry-service/internal/pods"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func TestPodLogsStreamOptionsFromQuery(t *testing.T) {
@@ -22,7 +25,7 @@ func TestPodLogsStreamOptionsFromQuery(t *testing.T) {
 			url:  "/api/v1/pods/logs/stream",
 			want: podLogsStreamOptions{
 				Format:    podLogsStreamFormatJSON,
-				Frequency: defaultPodLogsStreamFrequency,
+				Frequency: 500 * time.Millisecond,
 				FromStart: false,
 			},
 		},
@@ -36,6 +39,65 @@ func TestPodLogsStreamOptionsFromQuery(t *testing.T) {
 				TailLines: ptrInt64(40),
 			},
 		},
+		{
+			name: "parses container and filters",
+			url:  "/api/v1/pods/logs/stream?container=app&include=err(or)?&exclude=healthz&contains=GET&notContains=debug",
+			want: podLogsStreamOptions{
+				Format:    podLogsStreamFormatJSON,
+				Frequency: 500 * time.Millisecond,
+				Container: "app",
+				Filter: pods.LogFilter{
+					Include:     regexp.MustCompile("err(or)?"),
+					Exclude:     regexp.MustCompile("healthz"),
+					Contains:    "GET",
+					NotContains: "debug",
+				},
+			},
+		},
+		{
+			name: "parses container kinds",
+			url:  "/api/v1/pods/logs/stream?initContainers=true&ephemeralContainers=1",
+			want: podLogsStreamOptions{
+				Format:              podLogsStreamFormatJSON,
+				Frequency:           500 * time.Millisecond,
+				InitContainers:      true,
+				EphemeralContainers: true,
+			},
+		},
+		{
+			name: "defaults previous tail lines on restart",
+			url:  "/api/v1/pods/logs/stream?previousOnRestart=true",
+			want: podLogsStreamOptions{
+				Format:            podLogsStreamFormatJSON,
+				Frequency:         500 * time.Millisecond,
+				PreviousOnRestart: true,
+				PreviousTailLines: defaultPodLogsPreviousTailLines,
+			},
+		},
+		{
+			name:        "invalid previousTailLines",
+			url:         "/api/v1/pods/logs/stream?previousOnRestart=true&previousTailLines=-3",
+			wantErr:     true,
+			errContains: "previousTailLines must be >= 1",
+		},
+		{
+			name:        "invalid initContainers",
+			url:         "/api/v1/pods/logs/stream?initContainers=some",
+			wantErr:     true,
+			errContains: "invalid initContainers",
+		},
+		{
+			name:        "invalid include pattern",
+			url:         "/api/v1/pods/logs/stream?include=%28unclosed",
+			wantErr:     true,
+			errContains: "invalid include",
+		},
+		{
+			name:        "invalid exclude pattern",
+			url:         "/api/v1/pods/logs/stream?exclude=a%5B",
+			wantErr:     true,
+			errContains: "invalid exclude",
+		},
 		{
 			name:        "invalid format",
 			url:         "/api/v1/pods/logs/stream?format=xml",
@@ -72,7 +134,7 @@ func TestPodLogsStreamOptionsFromQuery(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			req := httptest.NewRequest("GET", tt.url, nil)
 
-			got, err := podLogsStreamOptionsFromQuery(req)
+			got, err := podLogsStreamOptionsFromQuery(req, config.Default().Logs.Stream)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -86,10 +148,132 @@ func TestPodLogsStreamOptionsFromQuery(t *testing.T) {
 	}
 }
 
+func TestPodPreviousLogsOptionsFromQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		want        podPreviousLogsOptions
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "parses explicit values",
+			url:  "/api/v1/pods/logs/previous?namespace=default&name=api-0&container=app&format=text&tailLines=100&sinceSeconds=600",
+			want: podPreviousLogsOptions{
+				Namespace:    "default",
+				Name:         "api-0",
+				Container:    "app",
+				Format:       podLogsStreamFormatText,
+				TailLines:    ptrInt64(100),
+				SinceSeconds: ptrInt64(600),
+			},
+		},
+		{
+			name:        "requires namespace",
+			url:         "/api/v1/pods/logs/previous?name=api-0",
+			wantErr:     true,
+			errContains: "namespace required",
+		},
+		{
+			name:        "requires name",
+			url:         "/api/v1/pods/logs/previous?namespace=default",
+			wantErr:     true,
+			errContains: "name required",
+		},
+		{
+			name:        "invalid sinceSeconds",
+			url:         "/api/v1/pods/logs/previous?namespace=default&name=api-0&sinceSeconds=0",
+			wantErr:     true,
+			errContains: "sinceSeconds must be >= 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := podPreviousLogsOptionsFromQuery(httptest.NewRequest("GET", tt.url, nil))
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPodLogsStreamScopeFromQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		want        pods.LogStreamScope
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "single namespace",
+			url:  "/api/v1/pods/logs/stream?namespace=default",
+			want: pods.LogStreamScope{Namespaces: []string{"default"}, Selector: labels.Everything()},
+		},
+		{
+			name: "namespace list, selector and pod names",
+			url:  "/api/v1/pods/logs/stream?namespace=staging-a,%20staging-b,staging-a&labelSelector=app%3Dcheckout&name=api-0,api-1",
+			want: pods.LogStreamScope{
+				Namespaces: []string{"staging-a", "staging-b"},
+				Selector:   labels.SelectorFromSet(labels.Set{"app": "checkout"}),
+				Names:      []string{"api-0", "api-1"},
+			},
+		},
+		{
+			name: "all namespaces",
+			url:  "/api/v1/pods/logs/stream?allNamespaces=true",
+			want: pods.LogStreamScope{Selector: labels.Everything()},
+		},
+		{
+			name:        "requires a namespace",
+			url:         "/api/v1/pods/logs/stream",
+			wantErr:     true,
+			errContains: "namespace required",
+		},
+		{
+			name:        "rejects namespace with allNamespaces",
+			url:         "/api/v1/pods/logs/stream?namespace=default&allNamespaces=true",
+			wantErr:     true,
+			errContains: "mutually exclusive",
+		},
+		{
+			name:        "rejects invalid selector",
+			url:         "/api/v1/pods/logs/stream?namespace=default&labelSelector=app%20in%20(",
+			wantErr:     true,
+			errContains: "invalid labelSelector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := podLogsStreamScopeFromQuery(httptest.NewRequest("GET", tt.url, nil))
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want.Namespaces, got.Namespaces)
+			require.Equal(t, tt.want.Names, got.Names)
+			require.Equal(t, tt.want.Selector.String(), got.Selector.String())
+		})
+	}
+}
+
 func TestWritePodLogStreamRecord(t *testing.T) {
 	record := pods.LogStreamRecord{
 		Namespace: "default",
 		Pod:       "api-0",
+		Container: "app",
 		Message:   "hello",
 		Timestamp: time.Date(2026, time.February, 19, 12, 0, 0, 0, time.UTC),
 	}
@@ -103,7 +287,7 @@ func TestWritePodLogStreamRecord(t *testing.T) {
 		{
 			name:   "writes json ndjson line",
 			format: podLogsStreamFormatJSON,
-			want:   "{\"namespace\":\"default\",\"pod\":\"api-0\",\"message\":\"hello\",\"timestamp\":\"2026-02-19T12:00:00Z\"}\n",
+			want:   "{\"namespace\":\"default\",\"pod\":\"api-0\",\"container\":\"app\",\"message\":\"hello\",\"timestamp\":\"2026-02-19T12:00:00Z\"}\n",
 		},
 		{
 			name:   "writes text line",
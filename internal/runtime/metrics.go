@@ -0,0 +1,175 @@
+package runtime
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var podPhases = []string{"Pending", "Running", "Succeeded", "Failed", "Unknown"}
+
+type promLabel struct {
+	Name  string
+	Value string
+}
+
+type promSample struct {
+	Labels []promLabel
+	Value  float64
+}
+
+type promGauge struct {
+	Name    string
+	Help    string
+	Samples []promSample
+}
+
+// writePrometheusMetrics renders the node and pod snapshots as gauges in the
+// Prometheus text exposition format.
+func writePrometheusMetrics(w io.Writer, nodeList []nodes.Node, podList []pods.Pod) error {
+	nodeList = append([]nodes.Node(nil), nodeList...)
+	sort.Slice(nodeList, func(i, j int) bool {
+		return nodeList[i].Name < nodeList[j].Name
+	})
+
+	podList = append([]pods.Pod(nil), podList...)
+	sort.Slice(podList, func(i, j int) bool {
+		if podList[i].Namespace != podList[j].Namespace {
+			return podList[i].Namespace < podList[j].Namespace
+		}
+		return podList[i].Name < podList[j].Name
+	})
+
+	var sb strings.Builder
+
+	for _, g := range append(nodeGauges(nodeList), podGauges(podList)...) {
+		writePromGauge(&sb, g)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func nodeGauges(nodeList []nodes.Node) []promGauge {
+	cpuUsed := promGauge{Name: "cluster_telemetry_node_cpu_used_cores", Help: "CPU used by the node, from metrics.k8s.io."}
+	cpuAlloc := promGauge{Name: "cluster_telemetry_node_cpu_allocatable_cores", Help: "CPU allocatable on the node."}
+	memUsed := promGauge{Name: "cluster_telemetry_node_memory_used_bytes", Help: "Memory used by the node, from metrics.k8s.io."}
+	memAlloc := promGauge{Name: "cluster_telemetry_node_memory_allocatable_bytes", Help: "Memory allocatable on the node."}
+	ready := promGauge{Name: "cluster_telemetry_node_ready", Help: "Whether the node reports Ready (1) or not (0)."}
+	conditions := promGauge{Name: "cluster_telemetry_node_condition", Help: "Node condition status, one series per condition and status."}
+
+	for _, n := range nodeList {
+		labels := []promLabel{{Name: "node", Value: n.Name}}
+
+		appendQuantitySample(&cpuUsed, labels, n.CPU.Used, quantityCores)
+		appendQuantitySample(&cpuAlloc, labels, n.CPU.Total, quantityCores)
+		appendQuantitySample(&memUsed, labels, n.Memory.Used, quantityBytes)
+		appendQuantitySample(&memAlloc, labels, n.Memory.Total, quantityBytes)
+
+		ready.Samples = append(ready.Samples, promSample{Labels: labels, Value: boolValue(n.Ready)})
+
+		for _, c := range n.Conditions {
+			conditions.Samples = append(conditions.Samples, promSample{
+				Labels: []promLabel{
+					{Name: "node", Value: n.Name},
+					{Name: "condition", Value: c.Type},
+					{Name: "status", Value: c.Status},
+				},
+				Value: 1,
+			})
+		}
+	}
+
+	return []promGauge{cpuUsed, cpuAlloc, memUsed, memAlloc, ready, conditions}
+}
+
+func podGauges(podList []pods.Pod) []promGauge {
+	phase := promGauge{Name: "cluster_telemetry_pod_phase", Help: "Pod phase, 1 for the current phase and 0 otherwise."}
+	ready := promGauge{Name: "cluster_telemetry_pod_ready", Help: "Whether all containers in the pod are ready (1) or not (0)."}
+	restarts := promGauge{Name: "cluster_telemetry_pod_restarts", Help: "Sum of container restarts in the pod."}
+
+	for _, p := range podList {
+		labels := []promLabel{
+			{Name: "node", Value: p.Node},
+			{Name: "namespace", Value: p.Namespace},
+			{Name: "pod", Value: p.Name},
+		}
+
+		for _, ph := range podPhases {
+			phaseLabels := append(append([]promLabel(nil), labels...), promLabel{Name: "phase", Value: ph})
+			phase.Samples = append(phase.Samples, promSample{Labels: phaseLabels, Value: boolValue(p.Phase == ph)})
+		}
+
+		ready.Samples = append(ready.Samples, promSample{Labels: labels, Value: boolValue(p.Ready)})
+		restarts.Samples = append(restarts.Samples, promSample{Labels: labels, Value: float64(p.Restarts)})
+	}
+
+	return []promGauge{phase, ready, restarts}
+}
+
+func quantityCores(q resource.Quantity) float64 {
+	return float64(q.MilliValue()) / 1000
+}
+
+func quantityBytes(q resource.Quantity) float64 {
+	return float64(q.Value())
+}
+
+// appendQuantitySample parses a snapshot quantity string such as "250m" or
+// "512Mi" and appends it to g. Empty or unparsable values are skipped.
+func appendQuantitySample(g *promGauge, labels []promLabel, raw string, convert func(resource.Quantity) float64) {
+	if raw == "" {
+		return
+	}
+
+	q, err := resource.ParseQuantity(raw)
+	if err != nil {
+		return
+	}
+
+	g.Samples = append(g.Samples, promSample{Labels: labels, Value: convert(q)})
+}
+
+func writePromGauge(w *strings.Builder, g promGauge) {
+	w.WriteString("# HELP " + g.Name + " " + g.Help + "\n")
+	w.WriteString("# TYPE " + g.Name + " gauge\n")
+
+	for _, s := range g.Samples {
+		w.WriteString(g.Name)
+
+		if len(s.Labels) > 0 {
+			w.WriteByte('{')
+			for i, l := range s.Labels {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				w.WriteString(l.Name + `="` + escapeLabelValue(l.Value) + `"`)
+			}
+			w.WriteByte('}')
+		}
+
+		w.WriteByte(' ')
+		w.WriteString(strconv.FormatFloat(s.Value, 'f', -1, 64))
+		w.WriteByte('\n')
+	}
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
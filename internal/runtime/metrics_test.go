@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWritePrometheusMetrics(t *testing.T) {
+	nodeList := []nodes.Node{
+		{
+			Name:   "node-b",
+			Ready:  false,
+			CPU:    nodes.Usage{Used: "", Total: "4000m"},
+			Memory: nodes.Usage{Used: "", Total: "8192Mi"},
+		},
+		{
+			Name:   "node-a",
+			Ready:  true,
+			CPU:    nodes.Usage{Used: "250m", Total: "2000m"},
+			Memory: nodes.Usage{Used: "512Mi", Total: "4096Mi"},
+			Conditions: []nodes.Condition{
+				{Type: "Ready", Status: "True"},
+			},
+		},
+	}
+
+	podList := []pods.Pod{
+		{Name: "api-0", Namespace: "default", Node: "node-a", Phase: "Running", Ready: true, Restarts: 3},
+	}
+
+	var out strings.Builder
+	require.NoError(t, writePrometheusMetrics(&out, nodeList, podList))
+
+	got := out.String()
+
+	wantLines := []string{
+		"# TYPE cluster_telemetry_node_cpu_used_cores gauge",
+		`cluster_telemetry_node_cpu_used_cores{node="node-a"} 0.25`,
+		`cluster_telemetry_node_cpu_allocatable_cores{node="node-b"} 4`,
+		`cluster_telemetry_node_memory_used_bytes{node="node-a"} 536870912`,
+		`cluster_telemetry_node_ready{node="node-a"} 1`,
+		`cluster_telemetry_node_ready{node="node-b"} 0`,
+		`cluster_telemetry_node_condition{node="node-a",condition="Ready",status="True"} 1`,
+		`cluster_telemetry_pod_phase{node="node-a",namespace="default",pod="api-0",phase="Running"} 1`,
+		`cluster_telemetry_pod_phase{node="node-a",namespace="default",pod="api-0",phase="Pending"} 0`,
+		`cluster_telemetry_pod_ready{node="node-a",namespace="default",pod="api-0"} 1`,
+		`cluster_telemetry_pod_restarts{node="node-a",namespace="default",pod="api-0"} 3`,
+	}
+
+	for _, line := range wantLines {
+		require.Contains(t, got, line+"\n")
+	}
+
+	require.NotContains(t, got, `cluster_telemetry_node_cpu_used_cores{node="node-b"}`)
+	require.Less(t, strings.Index(got, `{node="node-a"} 1`), strings.Index(got, `{node="node-b"} 0`))
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	require.Equal(t, `a\\b\"c\nd`, escapeLabelValue("a\\b\"c\nd"))
+}
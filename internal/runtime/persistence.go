@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/config"
+	"github.com/JNickson/cluster-telemetry-service/internal/store"
+)
+
+// attachStoreBackend restores st from the file at cfg.Path and keeps it
+// saved there. It returns the compaction interval, or zero when no path is
+// set and the store stays in memory.
+func attachStoreBackend(st *store.Store, cfg config.StoreConfig) (time.Duration, error) {
+	if cfg.Path == "" {
+		return 0, nil
+	}
+
+	path, retention, interval := cfg.Path, cfg.Retention.Duration, cfg.CompactInterval.Duration
+
+	backend, err := store.OpenFileBackend(path, retention)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := st.Attach(backend); err != nil {
+		_ = backend.Close()
+		return 0, err
+	}
+
+	slog.Info("persisting store", "path", path, "retention", retention, "compactInterval", interval)
+	return interval, nil
+}
+
+// runCompactor compacts the store's log every compactInterval until ctx is
+// done. The final compaction happens when the store is closed.
+func (a *App) runCompactor(ctx context.Context) {
+	ticker := time.NewTicker(a.compactInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := a.store.Compact(); err != nil {
+				slog.Error("failed to compact store", "error", err)
+				continue
+			}
+			slog.Debug("store compacted", "took", time.Since(start))
+		}
+	}
+}
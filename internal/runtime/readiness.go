@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/handlers"
+	"github.com/JNickson/cluster-telemetry-service/internal/store"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+)
+
+func (a *App) readinessChecks() []handlers.ReadinessCheck {
+	return []handlers.ReadinessCheck{
+		{Name: "informers", Check: func() error {
+			if !a.manager.HasSynced() {
+				return errors.New("informer caches not synced")
+			}
+			return nil
+		}},
+		{Name: "nodes", Check: snapshotFreshness(a.store.NodesMeta, a.maxStaleness)},
+		{Name: "pods", Check: snapshotFreshness(a.store.PodsMeta, a.maxStaleness)},
+		{Name: "workloads", Check: snapshotFreshness(a.store.WorkloadsMeta, a.maxStaleness)},
+	}
+}
+
+// snapshotFreshness fails until the first snapshot is stored and whenever
+// the last successful refresh is older than maxStaleness, e.g. because
+// rebuilds keep failing.
+func snapshotFreshness(snapshotMeta func() store.SnapshotMeta, maxStaleness time.Duration) func() error {
+	return func() error {
+		meta := snapshotMeta()
+		if meta.BuiltAt.IsZero() {
+			if meta.Failing() {
+				return fmt.Errorf("no snapshot yet: %s", meta.LastError)
+			}
+			return errors.New("no snapshot yet")
+		}
+
+		if age := utils.Now().Sub(meta.BuiltAt); age > maxStaleness {
+			err := fmt.Errorf(
+				"snapshot is %s old, exceeds %s",
+				age.Truncate(time.Second),
+				maxStaleness,
+			)
+			if meta.Failing() {
+				err = fmt.Errorf("%w: %s", err, meta.LastError)
+			}
+			return err
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,36 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/store"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotFreshness(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := utils.Now
+	utils.Now = func() time.Time { return now }
+	defer func() { utils.Now = originalNow }()
+
+	st := store.New()
+	check := snapshotFreshness(st.PodsMeta, time.Minute)
+
+	require.EqualError(t, check(), "no snapshot yet")
+
+	st.ReplacePods([]pods.Pod{{Namespace: "default", Name: "api-0"}}, 0)
+	require.NoError(t, check())
+
+	now = now.Add(time.Minute)
+	require.NoError(t, check())
+
+	now = now.Add(30 * time.Second)
+	require.EqualError(t, check(), "snapshot is 1m30s old, exceeds 1m0s")
+
+	st.PodsBuildFailed(errors.New("failed to list pods"))
+	require.EqualError(t, check(), "snapshot is 1m30s old, exceeds 1m0s: failed to list pods")
+}
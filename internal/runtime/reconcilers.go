@@ -4,52 +4,152 @@ import (
 	"context"
 	"log/slog"
 	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/summary"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
-func (a *App) startNodeReconciler(ctx context.Context) {
-	ticker := time.NewTicker(30 * time.Second)
+// changeTrigger coalesces informer notifications into a single pending
+// signal. Notify never blocks.
+type changeTrigger chan struct{}
+
+func newChangeTrigger() changeTrigger {
+	return make(changeTrigger, 1)
+}
+
+func (t changeTrigger) Notify() {
+	select {
+	case t <- struct{}{}:
+	default:
+	}
+}
+
+// runReconciler calls refresh once immediately, then after every burst of
+// changes (once debounce has elapsed since the first change in the burst),
+// and every interval as a safety net.
+func runReconciler(
+	ctx context.Context,
+	interval time.Duration,
+	debounce time.Duration,
+	changes <-chan struct{},
+	refresh func(context.Context),
+) {
+	refresh(ctx)
+
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	var (
+		debounceTimer *time.Timer
+		debounceC     <-chan time.Time
+	)
+	defer func() {
+		if debounceTimer != nil {
+			debounceTimer.Stop()
+		}
+	}()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			nodes, err := a.nodesService.BuildSnapshot(ctx)
-			if err != nil {
-				slog.Error("failed to refresh nodes", "error", err)
-				continue
+		case <-changes:
+			if debounceC == nil {
+				debounceTimer = time.NewTimer(debounce)
+				debounceC = debounceTimer.C
 			}
-			a.store.ReplaceNodes(nodes)
-			slog.Info("nodes snapshot refreshed",
-				"count", len(nodes),
-				"time", time.Now(),
-			)
+		case <-debounceC:
+			debounceC = nil
+			refresh(ctx)
+			ticker.Reset(interval)
+		case <-ticker.C:
+			refresh(ctx)
 		}
 	}
 }
 
+func (a *App) startNodeReconciler(ctx context.Context) {
+	runReconciler(ctx, a.refreshInterval, a.refreshDebounce, a.nodeChanges, a.refreshNodes)
+}
+
 func (a *App) startPodReconciler(ctx context.Context) {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
+	runReconciler(ctx, a.refreshInterval, a.refreshDebounce, a.podChanges, a.refreshPods)
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			pods, err := a.podsService.BuildSnapshot(ctx)
-			if err != nil {
-				slog.Error("failed to refresh pods", "error", err)
-				continue
-			}
+func (a *App) startWorkloadReconciler(ctx context.Context) {
+	runReconciler(ctx, a.refreshInterval, a.refreshDebounce, a.workloadChanges, a.refreshWorkloads)
+}
+
+func (a *App) refreshNodes(ctx context.Context) {
+	start := time.Now()
+	nodes, err := a.nodesService.BuildSnapshot(ctx)
+	if err != nil {
+		a.store.NodesBuildFailed(err)
+		slog.Error("failed to refresh nodes", "error", err)
+		return
+	}
+	took := time.Since(start)
 
-			a.store.ReplacePods(pods)
+	a.store.ReplaceNodes(nodes, took)
+	a.refreshSummary()
+	slog.Info("nodes snapshot refreshed",
+		"count", len(nodes),
+		"took", took,
+		"time", time.Now(),
+	)
+}
 
-			slog.Info("pods snapshot refreshed",
-				"count", len(pods),
-				"time", time.Now(),
-			)
-		}
+func (a *App) refreshPods(ctx context.Context) {
+	start := time.Now()
+	pods, err := a.podsService.BuildSnapshot(ctx)
+	if err != nil {
+		a.store.PodsBuildFailed(err)
+		slog.Error("failed to refresh pods", "error", err)
+		return
+	}
+	took := time.Since(start)
+
+	a.store.ReplacePods(pods, took)
+	a.refreshSummary()
+
+	slog.Info("pods snapshot refreshed",
+		"count", len(pods),
+		"took", took,
+		"time", time.Now(),
+	)
+}
+
+func (a *App) refreshWorkloads(ctx context.Context) {
+	start := time.Now()
+	workloads, err := a.workloadsService.BuildSnapshot(ctx)
+	if err != nil {
+		a.store.WorkloadsBuildFailed(err)
+		slog.Error("failed to refresh workloads", "error", err)
+		return
+	}
+	took := time.Since(start)
+
+	a.store.ReplaceWorkloads(workloads, took)
+
+	slog.Info("workloads snapshot refreshed",
+		"count", len(workloads),
+		"took", took,
+		"time", time.Now(),
+	)
+}
+
+// refreshSummary rebuilds the cluster summary from the stored snapshots. The
+// node and pod reconcilers both call it, so builds are serialised to keep an
+// older rollup from overwriting a newer one.
+func (a *App) refreshSummary() {
+	a.summaryMu.Lock()
+	defer a.summaryMu.Unlock()
+
+	podList, err := a.podLister.List(labels.Everything())
+	if err != nil {
+		slog.Error("failed to list pods for summary", "error", err)
+		return
 	}
+
+	a.store.ReplaceSummary(summary.Build(a.store.ListNodes(), podList, a.store.ListPods(), a.nodePoolLabel))
 }
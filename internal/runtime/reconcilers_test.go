@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunReconcilerCoalescesChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := newChangeTrigger()
+
+	var refreshes atomic.Int32
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		runReconciler(ctx, time.Hour, 50*time.Millisecond, changes, func(context.Context) {
+			refreshes.Add(1)
+		})
+	}()
+
+	require.Eventually(t, func() bool { return refreshes.Load() == 1 }, time.Second, time.Millisecond)
+
+	for i := 0; i < 20; i++ {
+		changes.Notify()
+	}
+
+	require.Eventually(t, func() bool { return refreshes.Load() == 2 }, time.Second, time.Millisecond)
+	require.Never(t, func() bool { return refreshes.Load() > 2 }, 150*time.Millisecond, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestRunReconcilerRefreshesOnInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var refreshes atomic.Int32
+	go runReconciler(ctx, 10*time.Millisecond, time.Hour, newChangeTrigger(), func(context.Context) {
+		refreshes.Add(1)
+	})
+
+	require.Eventually(t, func() bool { return refreshes.Load() >= 3 }, time.Second, time.Millisecond)
+}
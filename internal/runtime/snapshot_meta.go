@@ -0,0 +1,104 @@
+package runtime
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/store"
+)
+
+const (
+	snapshotGenerationHeader    = "X-Snapshot-Generation"
+	snapshotBuiltAtHeader       = "X-Snapshot-Built-At"
+	snapshotBuildDurationHeader = "X-Snapshot-Build-Duration-Ms"
+)
+
+// listEnvelope is the body of a list response with envelope=true.
+type listEnvelope[T any] struct {
+	Metadata listMetadata `json:"metadata"`
+	Items    []T          `json:"items"`
+}
+
+type listMetadata struct {
+	Generation      uint64     `json:"generation"`
+	BuiltAt         *time.Time `json:"builtAt"`
+	BuildDurationMs int64      `json:"buildDurationMs"`
+	LastError       string     `json:"lastError,omitempty"`
+	LastErrorAt     *time.Time `json:"lastErrorAt,omitempty"`
+
+	Total    int    `json:"total"`
+	Continue string `json:"continue,omitempty"`
+}
+
+func newListMetadata(meta store.SnapshotMeta, total int, next string) listMetadata {
+	return listMetadata{
+		Generation:      meta.Generation,
+		BuiltAt:         timePtr(meta.BuiltAt),
+		BuildDurationMs: meta.BuildDuration.Milliseconds(),
+		LastError:       meta.LastError,
+		LastErrorAt:     timePtr(meta.LastErrorAt),
+		Total:           total,
+		Continue:        next,
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// writeSnapshotHeaders sets the snapshot metadata and ETag headers. It
+// answers 304 Not Modified and returns true when the client already has the
+// current generation, in which case the caller must not write a body.
+func writeSnapshotHeaders(w http.ResponseWriter, r *http.Request, meta store.SnapshotMeta) bool {
+	etag := snapshotETag(meta)
+
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set(snapshotGenerationHeader, strconv.FormatUint(meta.Generation, 10))
+	if !meta.BuiltAt.IsZero() {
+		h.Set(snapshotBuiltAtHeader, meta.BuiltAt.UTC().Format(time.RFC3339Nano))
+		h.Set(snapshotBuildDurationHeader, strconv.FormatInt(meta.BuildDuration.Milliseconds(), 10))
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	return false
+}
+
+// snapshotETag identifies a snapshot generation. The generation only moves
+// when the contents change, so rebuilds that only refresh ages and usage
+// keep the tag. It restarts with the process, so the time it last moved is
+// added to keep a tag from an earlier process from matching an unrelated
+// snapshot.
+func snapshotETag(meta store.SnapshotMeta) string {
+	tag := strconv.FormatUint(meta.Generation, 10)
+	if !meta.ChangedAt.IsZero() {
+		tag += "-" + strconv.FormatInt(meta.ChangedAt.UnixNano(), 36)
+	}
+	return `"` + tag + `"`
+}
+
+// etagMatches implements the weak comparison If-None-Match calls for.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
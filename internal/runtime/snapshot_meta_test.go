@@ -0,0 +1,87 @@
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSnapshotHeaders(t *testing.T) {
+	meta := store.SnapshotMeta{
+		Generation:    7,
+		ChangedAt:     time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC),
+		BuiltAt:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		BuildDuration: 120 * time.Millisecond,
+	}
+
+	etag := `"7-` + strconv.FormatInt(meta.ChangedAt.UnixNano(), 36) + `"`
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "no validator", wantStatus: http.StatusOK},
+		{name: "current generation", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "weak validator in list", ifNoneMatch: `"5", W/` + etag, wantStatus: http.StatusNotModified},
+		{name: "wildcard", ifNoneMatch: "*", wantStatus: http.StatusNotModified},
+		{name: "older generation", ifNoneMatch: `"6"`, wantStatus: http.StatusOK},
+		{name: "same generation from another process", ifNoneMatch: `"7"`, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/nodes", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+
+			if !writeSnapshotHeaders(rec, req, meta) {
+				rec.WriteHeader(http.StatusOK)
+			}
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			require.Equal(t, etag, rec.Header().Get("ETag"))
+			require.Equal(t, "7", rec.Header().Get(snapshotGenerationHeader))
+			require.Equal(t, "2026-01-01T00:00:00Z", rec.Header().Get(snapshotBuiltAtHeader))
+			require.Equal(t, "120", rec.Header().Get(snapshotBuildDurationHeader))
+		})
+	}
+}
+
+func TestWriteListEnvelope(t *testing.T) {
+	meta := store.SnapshotMeta{
+		Generation:    3,
+		BuiltAt:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		BuildDuration: 45 * time.Millisecond,
+		LastError:     "failed to list nodes",
+		LastErrorAt:   time.Date(2025, 12, 31, 23, 59, 0, 0, time.UTC),
+	}
+
+	rec := httptest.NewRecorder()
+	writeList(rec, []string{"a", "b"}, 5, "Mg", meta, listOptions{Envelope: true})
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{
+		"metadata": {
+			"generation": 3,
+			"builtAt": "2026-01-01T00:00:00Z",
+			"buildDurationMs": 45,
+			"lastError": "failed to list nodes",
+			"lastErrorAt": "2025-12-31T23:59:00Z",
+			"total": 5,
+			"continue": "Mg"
+		},
+		"items": ["a", "b"]
+	}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	writeList(rec, []string{"a", "b"}, 5, "Mg", meta, listOptions{})
+	require.JSONEq(t, `["a", "b"]`, rec.Body.String())
+}
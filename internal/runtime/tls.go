@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/certs"
+	"github.com/JNickson/cluster-telemetry-service/internal/config"
+)
+
+// tlsSettings is the TLS setup built from config.TLSConfig. reloader is nil
+// when the server listens in plain HTTP.
+type tlsSettings struct {
+	reloader       *certs.Reloader
+	reloadInterval time.Duration
+	clientAuth     tls.ClientAuthType
+}
+
+// clientCerts reports whether client certificates are verified.
+func (s tlsSettings) clientCerts() bool {
+	return s.reloader != nil && s.clientAuth != tls.NoClientCert
+}
+
+// newTLSSettings loads the certificate when TLS is configured; the server
+// listens in plain HTTP otherwise.
+func newTLSSettings(cfg config.TLSConfig) (tlsSettings, error) {
+	if cfg.CertFile == "" {
+		return tlsSettings{}, nil
+	}
+
+	clientAuth := tls.NoClientCert
+	if cfg.ClientCAFile != "" {
+		switch cfg.ClientAuth {
+		case config.TLSClientAuthRequire:
+			clientAuth = tls.RequireAndVerifyClientCert
+		case config.TLSClientAuthOptional:
+			clientAuth = tls.VerifyClientCertIfGiven
+		default:
+			return tlsSettings{}, fmt.Errorf("invalid TLS client auth: %s", cfg.ClientAuth)
+		}
+	}
+
+	reloader, err := certs.NewReloader(cfg.CertFile, cfg.KeyFile, cfg.ClientCAFile)
+	if err != nil {
+		return tlsSettings{}, err
+	}
+
+	interval := cfg.ReloadInterval.Duration
+	slog.Info("serving TLS", "cert", cfg.CertFile, "clientCA", cfg.ClientCAFile, "clientAuth", clientAuth.String(), "reloadInterval", interval)
+	return tlsSettings{reloader: reloader, reloadInterval: interval, clientAuth: clientAuth}, nil
+}
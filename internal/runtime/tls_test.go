@@ -0,0 +1,21 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTLSSettings(t *testing.T) {
+	settings, err := newTLSSettings(config.TLSConfig{})
+	require.NoError(t, err)
+	require.Nil(t, settings.reloader)
+	require.False(t, settings.clientCerts())
+
+	_, err = newTLSSettings(config.TLSConfig{CertFile: "/etc/tls/tls.crt", KeyFile: "/etc/tls/tls.key", ClientCAFile: "/etc/tls/ca.crt", ClientAuth: "sometimes"})
+	require.ErrorContains(t, err, "invalid TLS client auth")
+
+	_, err = newTLSSettings(config.TLSConfig{CertFile: "/etc/tls/tls.crt", KeyFile: "/etc/tls/tls.key", ClientAuth: config.TLSClientAuthOptional})
+	require.ErrorContains(t, err, "read certificate")
+}
@@ -0,0 +1,161 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/store"
+)
+
+const (
+	watchEventList       = "LIST"
+	watchKeepalivePeriod = 15 * time.Second
+)
+
+// watchSource adapts the store's per-kind list and change history to the
+// generic SSE handler. allow, when set, hides the objects the caller may not
+// see from both the list and the events, and redact, when set, strips the
+// fields they may not see from the objects that are sent.
+type watchSource[T any] struct {
+	list   func() ([]T, uint64)
+	since  func(uint64) ([]store.WatchEvent[T], <-chan struct{}, error)
+	allow  func(T) bool
+	redact func(T) T
+}
+
+// resumeVersionFromRequest reads the resource version to resume from, taken
+// from the resourceVersion query parameter or the Last-Event-ID header that
+// EventSource sends on reconnect. It returns 0 when the client is not resuming.
+func resumeVersionFromRequest(r *http.Request) (uint64, error) {
+	raw := r.URL.Query().Get("resourceVersion")
+	if raw == "" {
+		raw = r.Header.Get("Last-Event-ID")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+
+	v, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid resourceVersion: %w", err)
+	}
+
+	return v, nil
+}
+
+// serveWatch streams an initial LIST event followed by ADDED, MODIFIED and
+// DELETED events as Server-Sent Events. When the client resumes from a
+// resource version still in the history, the LIST is skipped and the missed
+// events are replayed instead.
+func serveWatch[T any](w http.ResponseWriter, r *http.Request, src watchSource[T]) {
+	version, err := resumeVersionFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var initial []T
+	resuming := version > 0
+
+	if resuming {
+		if _, _, err := src.since(version); err != nil {
+			if errors.Is(err, store.ErrResourceVersionTooOld) {
+				http.Error(w, err.Error(), http.StatusGone)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else {
+		initial, version = src.list()
+		if src.allow != nil {
+			initial = slices.DeleteFunc(initial, func(item T) bool { return !src.allow(item) })
+		}
+		if src.redact != nil {
+			for i := range initial {
+				initial[i] = src.redact(initial[i])
+			}
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !resuming {
+		if initial == nil {
+			initial = []T{}
+		}
+		if err := writeSSEEvent(w, version, watchEventList, initial); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(watchKeepalivePeriod)
+	defer keepalive.Stop()
+
+	for {
+		events, changed, err := src.since(version)
+		if err != nil {
+			// The watcher fell behind the retained history; ending the
+			// stream makes the client reconnect and get a 410.
+			slog.Warn("watch stream ended", "path", r.URL.Path, "resourceVersion", version, "error", err)
+			return
+		}
+
+		for _, ev := range events {
+			if src.allow != nil && !src.allow(ev.Object) {
+				version = ev.ResourceVersion
+				continue
+			}
+			obj := ev.Object
+			if src.redact != nil {
+				obj = src.redact(obj)
+			}
+			if err := writeSSEEvent(w, ev.ResourceVersion, string(ev.Type), obj); err != nil {
+				return
+			}
+			version = ev.ResourceVersion
+		}
+		if len(events) > 0 {
+			flusher.Flush()
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		case <-keepalive.C:
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSEEvent(w io.Writer, id uint64, event string, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, event, b)
+	return err
+}
@@ -0,0 +1,132 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeWatch(t *testing.T) {
+	st := store.New()
+	_, base := st.ListPodsWithVersion()
+	id := func(offset uint64) string { return strconv.FormatUint(base+offset, 10) }
+
+	st.ReplacePods([]pods.Pod{{Namespace: "default", Name: "api-0", Phase: "Pending"}}, 0)
+	st.ReplacePods([]pods.Pod{
+		{Namespace: "default", Name: "api-0", Phase: "Running"},
+		{Namespace: "default", Name: "api-1", Phase: "Running"},
+	}, 0)
+
+	src := watchSource[pods.Pod]{list: st.ListPodsWithVersion, since: st.PodEventsSince}
+
+	tests := []struct {
+		name       string
+		url        string
+		lastID     string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "sends initial list",
+			url:        "/api/v1/pods/watch",
+			wantStatus: http.StatusOK,
+			wantBody:   "id: " + id(3) + "\nevent: LIST\ndata: [{\"name\":\"api-0\"",
+		},
+		{
+			name:       "resumes from Last-Event-ID",
+			url:        "/api/v1/pods/watch",
+			lastID:     id(1),
+			wantStatus: http.StatusOK,
+			wantBody:   "id: " + id(2) + "\nevent: MODIFIED\ndata: {\"name\":\"api-0\"",
+		},
+		{
+			name:       "resumes from resourceVersion",
+			url:        "/api/v1/pods/watch?resourceVersion=" + id(2),
+			wantStatus: http.StatusOK,
+			wantBody:   "id: " + id(3) + "\nevent: ADDED\ndata: {\"name\":\"api-1\"",
+		},
+		{
+			name:       "rejects unknown resource version",
+			url:        "/api/v1/pods/watch?resourceVersion=" + id(99),
+			wantStatus: http.StatusGone,
+		},
+		{
+			name:       "rejects resource version from an earlier process",
+			url:        "/api/v1/pods/watch",
+			lastID:     "2",
+			wantStatus: http.StatusGone,
+		},
+		{
+			name:       "rejects invalid resource version",
+			url:        "/api/v1/pods/watch?resourceVersion=abc",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			req := httptest.NewRequest("GET", tt.url, nil).WithContext(ctx)
+			if tt.lastID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastID)
+			}
+			rec := httptest.NewRecorder()
+
+			serveWatch(rec, req, src)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+				require.Contains(t, rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestServeWatchStreamsChanges(t *testing.T) {
+	st := store.New()
+	src := watchSource[pods.Pod]{list: st.ListPodsWithVersion, since: st.PodEventsSince}
+	_, base := st.ListPodsWithVersion()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveWatch(w, r, src)
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		var sb strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\n" {
+				return sb.String()
+			}
+			sb.WriteString(line)
+		}
+	}
+
+	require.Equal(t, fmt.Sprintf("id: %d\nevent: LIST\ndata: []\n", base), readEvent())
+
+	st.ReplacePods([]pods.Pod{{Namespace: "default", Name: "api-0"}}, 0)
+	require.Contains(t, readEvent(), fmt.Sprintf("id: %d\nevent: ADDED\n", base+1))
+
+	st.ReplacePods(nil, 0)
+	require.Contains(t, readEvent(), fmt.Sprintf("id: %d\nevent: DELETED\n", base+2))
+}
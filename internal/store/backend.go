@@ -0,0 +1,71 @@
+package store
+
+import (
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
+	"github.com/JNickson/cluster-telemetry-service/internal/history"
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/summary"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
+)
+
+// Backend persists the state of a Store so it survives restarts. Changes
+// are appended as records; Compact replaces everything written so far with
+// a single checkpoint record. Without a backend the Store keeps its state in
+// memory only.
+type Backend interface {
+	// Load returns the saved records in the order they were written.
+	Load() ([]Record, error)
+	Append(rec Record) error
+
+	// BeginCompact marks the point the next checkpoint is taken at. Records
+	// appended after it are kept when Compact replaces the log, so the
+	// checkpoint can be written without holding up appends.
+	BeginCompact()
+	Compact(checkpoint Record) error
+
+	Close() error
+}
+
+type RecordType string
+
+const (
+	RecordCheckpoint    RecordType = "checkpoint"
+	RecordNodeSamples   RecordType = "nodeSamples"
+	RecordPodSamples    RecordType = "podSamples"
+	RecordEventUpserted RecordType = "eventUpserted"
+	RecordEventDeleted  RecordType = "eventDeleted"
+)
+
+// Record is one persisted change. Only the field matching Type is set; a
+// deleted event only carries its namespace and name.
+type Record struct {
+	Type RecordType `json:"type"`
+	At   time.Time  `json:"at"`
+
+	Checkpoint  *Checkpoint                   `json:"checkpoint,omitempty"`
+	NodeSamples map[string]history.NodeSample `json:"nodeSamples,omitempty"`
+	PodSamples  map[string]history.PodSample  `json:"podSamples,omitempty"`
+	Event       *events.Event                 `json:"event,omitempty"`
+}
+
+// Checkpoint is the full state of a Store at the time of a compaction.
+type Checkpoint struct {
+	Nodes     []nodes.Node `json:"nodes"`
+	NodesMeta SnapshotMeta `json:"nodesMeta"`
+
+	Pods     []pods.Pod   `json:"pods"`
+	PodsMeta SnapshotMeta `json:"podsMeta"`
+
+	Workloads     []workloads.Workload `json:"workloads"`
+	WorkloadsMeta SnapshotMeta         `json:"workloadsMeta"`
+
+	// Summary is nil until the first one was built. It is saved rather than
+	// rebuilt on load because it also counts pods the snapshot leaves out.
+	Summary *summary.Summary `json:"summary,omitempty"`
+
+	Events  []events.Event `json:"events"`
+	History history.Dump   `json:"history"`
+}
@@ -0,0 +1,222 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+)
+
+// FileBackend is an append-only file of JSON records, one per line.
+// Compaction writes the checkpoint to a temporary file and renames it over
+// the log, so a crash leaves either the old or the new file in place.
+type FileBackend struct {
+	mu        sync.Mutex
+	path      string
+	retention time.Duration
+	file      *os.File
+
+	// compacting is set between BeginCompact and Compact; the lines
+	// appended meanwhile are collected in pending and copied after the
+	// checkpoint.
+	compacting bool
+	pending    [][]byte
+}
+
+var _ Backend = (*FileBackend)(nil)
+
+// OpenFileBackend opens or creates the log at path. Records older than
+// retention are skipped when loading, so a long outage does not bring back
+// stale snapshots.
+func OpenFileBackend(path string, retention time.Duration) (*FileBackend, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	file, err := openAppend(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileBackend{
+		path:      path,
+		retention: retention,
+		file:      file,
+	}, nil
+}
+
+func openAppend(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store file: %w", err)
+	}
+	return file, nil
+}
+
+// Load reads the records in the log. A record cut short by a crash ends the
+// log; everything before it is returned.
+func (b *FileBackend) Load() ([]Record, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	file, err := os.Open(b.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	cutoff := utils.Now().Add(-b.retention)
+
+	var out []Record
+	dec := json.NewDecoder(file)
+	for {
+		var rec Record
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			slog.Warn("ignoring unreadable end of store file", "path", b.path, "records", len(out), "error", err)
+			return out, nil
+		}
+
+		if rec.At.Before(cutoff) {
+			continue
+		}
+		out = append(out, rec)
+	}
+}
+
+func (b *FileBackend) Append(rec Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.file == nil {
+		return os.ErrClosed
+	}
+
+	line = append(line, '\n')
+	if _, err := b.file.Write(line); err != nil {
+		return err
+	}
+
+	if b.compacting {
+		b.pending = append(b.pending, line)
+	}
+	return nil
+}
+
+func (b *FileBackend) BeginCompact() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.compacting = true
+	b.pending = nil
+}
+
+// Compact writes the checkpoint without holding the backend lock, so
+// appends carry on meanwhile; only copying the records appended since
+// BeginCompact and swapping the files block them.
+func (b *FileBackend) Compact(checkpoint Record) error {
+	defer b.endCompact()
+
+	line, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp := b.path + ".tmp"
+	if err := writeFileSync(tmp, append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write store checkpoint: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.file == nil {
+		return os.ErrClosed
+	}
+
+	if err := appendFileSync(tmp, b.pending); err != nil {
+		return fmt.Errorf("failed to write store checkpoint: %w", err)
+	}
+
+	// The open handle keeps pointing at the old log until it is swapped,
+	// so a failed rename leaves appends working.
+	if err := os.Rename(tmp, b.path); err != nil {
+		return fmt.Errorf("failed to replace store file: %w", err)
+	}
+
+	file, err := openAppend(b.path)
+	if err != nil {
+		return err
+	}
+
+	old := b.file
+	b.file = file
+	return old.Close()
+}
+
+func (b *FileBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.file == nil {
+		return nil
+	}
+
+	err := b.file.Close()
+	b.file = nil
+	return err
+}
+
+func (b *FileBackend) endCompact() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.compacting = false
+	b.pending = nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	return syncFile(path, os.O_TRUNC, [][]byte{data})
+}
+
+func appendFileSync(path string, lines [][]byte) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	return syncFile(path, os.O_APPEND, lines)
+}
+
+func syncFile(path string, flag int, chunks [][]byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|flag, 0o600)
+	if err != nil {
+		return err
+	}
+
+	for _, data := range chunks {
+		if _, err := file.Write(data); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,224 @@
+package store
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/summary"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+)
+
+// pendingSamples holds the latest history samples of the current resolution
+// bucket. They are appended to the backend once the next bucket starts, so
+// the log gets one record per bucket rather than one per refresh; the
+// current bucket is saved by the next compaction.
+type pendingSamples[T any] struct {
+	bucket  time.Time
+	at      time.Time
+	samples map[string]T
+}
+
+// next stores samples as the pending ones and returns the previous pending
+// samples when they belong to an earlier bucket.
+func (p *pendingSamples[T]) next(samples map[string]T, at time.Time, resolution time.Duration) (map[string]T, time.Time, bool) {
+	bucket := at.Truncate(resolution)
+	prev, prevAt := p.samples, p.at
+	flush := prev != nil && !bucket.Equal(p.bucket)
+
+	p.bucket, p.at, p.samples = bucket, at, samples
+	return prev, prevAt, flush
+}
+
+// Attach loads the state saved in b, replacing the current snapshots, and
+// from then on records every change to b. The loaded log is compacted
+// straight away, which also drops any record cut short by a crash.
+func (s *Store) Attach(b Backend) error {
+	records, err := b.Load()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for _, rec := range records {
+		s.applyLocked(rec)
+	}
+	s.backend = b
+	s.mu.Unlock()
+
+	slog.Info("store state loaded", "records", len(records))
+
+	return s.Compact()
+}
+
+func (s *Store) applyLocked(rec Record) {
+	switch rec.Type {
+	case RecordCheckpoint:
+		if rec.Checkpoint == nil {
+			return
+		}
+		cp := rec.Checkpoint
+
+		s.nodeWatch.record(s.nodes, cp.Nodes)
+		s.nodes = nonNil(cp.Nodes)
+		s.nodesMeta = cp.NodesMeta
+
+		s.podWatch.record(s.pods, cp.Pods)
+		s.pods = nonNil(cp.Pods)
+		s.podsMeta = cp.PodsMeta
+
+		s.workloads = nonNil(cp.Workloads)
+		s.workloadsMeta = cp.WorkloadsMeta
+
+		if cp.Summary != nil {
+			s.summary = *cp.Summary
+		}
+
+		for _, e := range cp.Events {
+			s.events.Upsert(e)
+		}
+		s.history.Restore(cp.History, utils.Now())
+	case RecordNodeSamples:
+		s.history.AddNodeSamples(rec.NodeSamples, rec.At)
+	case RecordPodSamples:
+		s.history.AddPodSamples(rec.PodSamples, rec.At)
+	case RecordEventUpserted:
+		if rec.Event != nil {
+			s.events.Upsert(*rec.Event)
+		}
+	case RecordEventDeleted:
+		if rec.Event != nil {
+			s.events.Delete(rec.Event.Namespace, rec.Event.Name)
+		}
+	}
+}
+
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return make([]T, 0)
+	}
+	return items
+}
+
+// Compact replaces the backend's log with a checkpoint of the current
+// state. Events and history past their retention are not part of it. It is
+// a no-op without a backend.
+func (s *Store) Compact() error {
+	s.compactMu.Lock()
+	defer s.compactMu.Unlock()
+
+	// The checkpoint is taken under the read lock, but marshalled and
+	// written after releasing it so informer handlers and snapshot
+	// replacement are not held up by the disk; the backend keeps whatever
+	// is appended in between.
+	s.mu.RLock()
+	backend := s.backend
+	if backend == nil {
+		s.mu.RUnlock()
+		return nil
+	}
+	backend.BeginCompact()
+
+	var sum *summary.Summary
+	if !s.summary.GeneratedAt.IsZero() {
+		sum = new(summary.Summary)
+		*sum = s.summary
+	}
+
+	checkpoint := Record{
+		Type: RecordCheckpoint,
+		At:   utils.Now(),
+		Checkpoint: &Checkpoint{
+			Nodes:         s.nodes,
+			NodesMeta:     s.nodesMeta,
+			Pods:          s.pods,
+			PodsMeta:      s.podsMeta,
+			Workloads:     s.workloads,
+			WorkloadsMeta: s.workloadsMeta,
+			Summary:       sum,
+			Events:        s.events.List(events.Filter{}),
+			History:       s.history.Dump(),
+		},
+	}
+	s.mu.RUnlock()
+
+	return backend.Compact(checkpoint)
+}
+
+// Close compacts and closes the backend. Later changes are kept in memory
+// only.
+func (s *Store) Close() error {
+	err := s.Compact()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.backend == nil {
+		return err
+	}
+
+	if closeErr := s.backend.Close(); err == nil {
+		err = closeErr
+	}
+	s.backend = nil
+	return err
+}
+
+// UpsertEvent adds or replaces an event in the event buffer.
+func (s *Store) UpsertEvent(e events.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events.Upsert(e)
+	s.appendLocked(Record{Type: RecordEventUpserted, At: utils.Now(), Event: &e})
+}
+
+// DeleteEvent removes an event from the event buffer.
+func (s *Store) DeleteEvent(namespace, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events.Delete(namespace, name)
+	s.appendLocked(Record{
+		Type:  RecordEventDeleted,
+		At:    utils.Now(),
+		Event: &events.Event{Namespace: namespace, Name: name},
+	})
+}
+
+func (s *Store) recordNodesLocked(list []nodes.Node, at time.Time) {
+	samples := s.history.RecordNodes(list, at)
+	if s.backend == nil {
+		return
+	}
+
+	if prev, prevAt, ok := s.pendingNodes.next(samples, at, s.history.Resolution()); ok {
+		s.appendLocked(Record{Type: RecordNodeSamples, At: prevAt, NodeSamples: prev})
+	}
+}
+
+func (s *Store) recordPodsLocked(list []pods.Pod, at time.Time) {
+	samples := s.history.RecordPods(list, at)
+	if s.backend == nil {
+		return
+	}
+
+	if prev, prevAt, ok := s.pendingPods.next(samples, at, s.history.Resolution()); ok {
+		s.appendLocked(Record{Type: RecordPodSamples, At: prevAt, PodSamples: prev})
+	}
+}
+
+// appendLocked writes rec to the backend, if any. Failures are logged
+// rather than returned: the in-memory state stays authoritative and the
+// next compaction rewrites the log.
+func (s *Store) appendLocked(rec Record) {
+	if s.backend == nil {
+		return
+	}
+
+	if err := s.backend.Append(rec); err != nil {
+		slog.Warn("failed to persist store change", "type", rec.Type, "error", err)
+	}
+}
@@ -0,0 +1,170 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/summary"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func withClock(t *testing.T, now *time.Time) {
+	t.Helper()
+	originalNow := utils.Now
+	utils.Now = func() time.Time { return *now }
+	t.Cleanup(func() { utils.Now = originalNow })
+}
+
+func openTestStore(t *testing.T, path string) *Store {
+	t.Helper()
+
+	backend, err := OpenFileBackend(path, 24*time.Hour)
+	require.NoError(t, err)
+
+	st := New()
+	require.NoError(t, st.Attach(backend))
+	return st
+}
+
+func TestStorePersistsAcrossRestarts(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	withClock(t, &now)
+	path := filepath.Join(t.TempDir(), "state", "store.log")
+
+	st := openTestStore(t, path)
+	for i := range 3 {
+		now = now.Add(time.Minute)
+		st.ReplaceNodes([]nodes.Node{{Name: "node-1", Ready: true, CPU: nodes.Usage{Used: "250m"}}}, 0)
+		st.ReplacePods([]pods.Pod{{Namespace: "default", Name: "api-0", Restarts: int32(i)}}, 0)
+	}
+	st.UpsertEvent(events.Event{Namespace: "default", Name: "e1", Reason: "BackOff", LastSeen: now})
+	st.UpsertEvent(events.Event{Namespace: "default", Name: "e2", Reason: "Pulled", LastSeen: now})
+	st.DeleteEvent("default", "e2")
+	st.ReplaceSummary(summary.Summary{GeneratedAt: now, Pods: summary.PodCounts{Total: 4}})
+
+	// Only appended records so far: the store is reopened without Close,
+	// as after a crash.
+	now = now.Add(time.Minute)
+	restored := openTestStore(t, path)
+
+	items, meta := restored.ListNodesWithMeta()
+	require.Empty(t, items, "snapshots are only saved by compaction")
+	require.Zero(t, meta.Generation)
+
+	require.Equal(t, []string{"e1"}, eventNames(restored.Events().List(events.Filter{})))
+
+	// The last bucket of samples is only saved by compaction.
+	samples, ok := restored.History().Pod("default", "api-0", now.Add(-time.Hour), now, time.Minute)
+	require.True(t, ok)
+	require.Len(t, samples, 2)
+
+	require.NoError(t, restored.Close())
+	require.NoError(t, st.Close())
+
+	restored = openTestStore(t, path)
+	items, meta = restored.ListNodesWithMeta()
+	require.Len(t, items, 1)
+	require.Equal(t, uint64(1), meta.Generation, "only the usage of the node changed")
+	require.Equal(t, uint64(3), restored.PodsMeta().Generation)
+	require.Len(t, restored.ListPods(), 1)
+	require.Equal(t, 4, restored.Summary().Pods.Total, "the summary is restored rather than rebuilt")
+
+	samples, _ = restored.History().Pod("default", "api-0", now.Add(-time.Hour), now, time.Minute)
+	require.Len(t, samples, 3)
+	require.Equal(t, int32(2), samples[2].Restarts)
+
+	// The restored snapshot is the starting point for watchers.
+	_, version := restored.ListNodesWithVersion()
+	added, _, err := restored.NodeEventsSince(version - 1)
+	require.NoError(t, err)
+	require.Equal(t, []EventType{EventAdded}, eventTypes(added))
+	_, _, err = restored.NodeEventsSince(version - 2)
+	require.ErrorIs(t, err, ErrResourceVersionTooOld)
+
+	require.NoError(t, restored.Close())
+}
+
+func TestFileBackendRetentionAndTruncatedTail(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	withClock(t, &now)
+	path := filepath.Join(t.TempDir(), "store.log")
+
+	backend, err := OpenFileBackend(path, time.Hour)
+	require.NoError(t, err)
+
+	old := events.Event{Namespace: "default", Name: "old", LastSeen: now}
+	recent := events.Event{Namespace: "default", Name: "recent", LastSeen: now.Add(2 * time.Hour)}
+	require.NoError(t, backend.Append(Record{Type: RecordEventUpserted, At: now, Event: &old}))
+	require.NoError(t, backend.Append(Record{Type: RecordEventUpserted, At: now.Add(2 * time.Hour), Event: &recent}))
+	require.NoError(t, backend.Close())
+
+	// A crash in the middle of a write leaves a partial record behind.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"type":"eventUpserted","at":"2026-01-01T02:00:00Z","event":{"name":`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	now = now.Add(2 * time.Hour)
+	backend, err = OpenFileBackend(path, time.Hour)
+	require.NoError(t, err)
+
+	records, err := backend.Load()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "recent", records[0].Event.Name)
+
+	// Attaching compacts the log into a single checkpoint.
+	st := New()
+	require.NoError(t, st.Attach(backend))
+
+	records, err = backend.Load()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, RecordCheckpoint, records[0].Type)
+	require.Len(t, records[0].Checkpoint.Events, 1)
+
+	require.NoError(t, st.Close())
+}
+
+func TestFileBackendKeepsAppendsDuringCompaction(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	withClock(t, &now)
+	path := filepath.Join(t.TempDir(), "store.log")
+
+	backend, err := OpenFileBackend(path, time.Hour)
+	require.NoError(t, err)
+
+	before := events.Event{Namespace: "default", Name: "before", LastSeen: now}
+	during := events.Event{Namespace: "default", Name: "during", LastSeen: now}
+	after := events.Event{Namespace: "default", Name: "after", LastSeen: now}
+
+	require.NoError(t, backend.Append(Record{Type: RecordEventUpserted, At: now, Event: &before}))
+	backend.BeginCompact()
+	require.NoError(t, backend.Append(Record{Type: RecordEventUpserted, At: now, Event: &during}))
+	require.NoError(t, backend.Compact(Record{Type: RecordCheckpoint, At: now, Checkpoint: &Checkpoint{Events: []events.Event{before}}}))
+	require.NoError(t, backend.Append(Record{Type: RecordEventUpserted, At: now, Event: &after}))
+
+	records, err := backend.Load()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, RecordCheckpoint, records[0].Type)
+	require.Equal(t, "during", records[1].Event.Name)
+	require.Equal(t, "after", records[2].Event.Name)
+
+	require.NoError(t, backend.Close())
+}
+
+func eventNames(items []events.Event) []string {
+	out := make([]string, 0, len(items))
+	for _, e := range items {
+		out = append(out, e.Name)
+	}
+	return out
+}
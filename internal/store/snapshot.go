@@ -0,0 +1,125 @@
+package store
+
+import (
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
+)
+
+// SnapshotMeta describes the most recent build of a snapshot.
+type SnapshotMeta struct {
+	// Generation increases by one on every successful build that changed
+	// the contents, ignoring age and usage as the watch streams do. It is
+	// one after the first build and zero before it.
+	Generation uint64
+	// ChangedAt is when Generation last increased.
+	ChangedAt     time.Time
+	BuiltAt       time.Time
+	BuildDuration time.Duration
+
+	// LastError is the error of the most recent failed build. It is kept
+	// after later successes; compare LastErrorAt with BuiltAt to tell
+	// whether the snapshot has recovered.
+	LastError   string
+	LastErrorAt time.Time
+}
+
+// Failing reports whether the most recent build attempt failed.
+func (m SnapshotMeta) Failing() bool {
+	return m.LastError != "" && m.LastErrorAt.After(m.BuiltAt)
+}
+
+func (m *SnapshotMeta) built(took time.Duration, changed bool) {
+	m.BuiltAt = utils.Now()
+	m.BuildDuration = took
+	if changed || m.Generation == 0 {
+		m.Generation++
+		m.ChangedAt = m.BuiltAt
+	}
+}
+
+func (m *SnapshotMeta) failed(err error) {
+	m.LastError = err.Error()
+	m.LastErrorAt = utils.Now()
+}
+
+// NodesMeta returns the metadata of the current node snapshot.
+func (s *Store) NodesMeta() SnapshotMeta {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.nodesMeta
+}
+
+// PodsMeta returns the metadata of the current pod snapshot.
+func (s *Store) PodsMeta() SnapshotMeta {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.podsMeta
+}
+
+// WorkloadsMeta returns the metadata of the current workload snapshot.
+func (s *Store) WorkloadsMeta() SnapshotMeta {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.workloadsMeta
+}
+
+// ListNodesWithMeta returns the nodes together with the metadata of the
+// snapshot they belong to.
+func (s *Store) ListNodesWithMeta() ([]nodes.Node, SnapshotMeta) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]nodes.Node, len(s.nodes))
+	copy(out, s.nodes)
+	return out, s.nodesMeta
+}
+
+// ListPodsWithMeta returns the pods together with the metadata of the
+// snapshot they belong to.
+func (s *Store) ListPodsWithMeta() ([]pods.Pod, SnapshotMeta) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]pods.Pod, len(s.pods))
+	copy(out, s.pods)
+	return out, s.podsMeta
+}
+
+// ListWorkloadsWithMeta returns the workloads together with the metadata of
+// the snapshot they belong to.
+func (s *Store) ListWorkloadsWithMeta() ([]workloads.Workload, SnapshotMeta) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]workloads.Workload, len(s.workloads))
+	copy(out, s.workloads)
+	return out, s.workloadsMeta
+}
+
+// NodesBuildFailed records a failed node snapshot build. The current
+// snapshot is kept.
+func (s *Store) NodesBuildFailed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodesMeta.failed(err)
+}
+
+// PodsBuildFailed records a failed pod snapshot build. The current snapshot
+// is kept.
+func (s *Store) PodsBuildFailed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.podsMeta.failed(err)
+}
+
+// WorkloadsBuildFailed records a failed workload snapshot build. The current
+// snapshot is kept.
+func (s *Store) WorkloadsBuildFailed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workloadsMeta.failed(err)
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSnapshotMeta(t *testing.T) {
+	st := New()
+
+	_, meta := st.ListNodesWithMeta()
+	require.Zero(t, meta.Generation)
+	require.True(t, meta.BuiltAt.IsZero())
+
+	st.NodesBuildFailed(errors.New("failed to list nodes"))
+	meta = st.NodesMeta()
+	require.Zero(t, meta.Generation)
+	require.True(t, meta.Failing())
+
+	st.ReplaceNodes([]nodes.Node{{Name: "node-1"}}, 250*time.Millisecond)
+	st.ReplaceNodes([]nodes.Node{{Name: "node-1"}}, 100*time.Millisecond)
+
+	items, meta := st.ListNodesWithMeta()
+	require.Len(t, items, 1)
+	require.Equal(t, uint64(1), meta.Generation)
+	require.Equal(t, 100*time.Millisecond, meta.BuildDuration)
+	require.False(t, meta.BuiltAt.IsZero())
+	require.Equal(t, "failed to list nodes", meta.LastError)
+	require.False(t, meta.Failing())
+
+	require.Zero(t, st.PodsMeta().Generation)
+}
+
+func TestStoreSnapshotGenerationTracksChanges(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	withClock(t, &now)
+	st := New()
+
+	st.ReplacePods(nil, 0)
+	first := st.PodsMeta()
+	require.Equal(t, uint64(1), first.Generation, "the first build counts even when empty")
+	require.Equal(t, now, first.ChangedAt)
+
+	now = now.Add(time.Minute)
+	pod := pods.Pod{Namespace: "default", Name: "api-0", Age: "1m", Usage: &pods.ResourceUsage{CPU: "10m"}}
+	st.ReplacePods([]pods.Pod{pod}, 0)
+	require.Equal(t, uint64(2), st.PodsMeta().Generation)
+
+	now = now.Add(time.Minute)
+	pod.Age, pod.Usage = "2m", &pods.ResourceUsage{CPU: "20m"}
+	st.ReplacePods([]pods.Pod{pod}, 0)
+	meta := st.PodsMeta()
+	require.Equal(t, uint64(2), meta.Generation, "age and usage alone keep the generation")
+	require.Equal(t, now, meta.BuiltAt)
+	require.Equal(t, now.Add(-time.Minute), meta.ChangedAt)
+
+	pod.Restarts = 1
+	st.ReplacePods([]pods.Pod{pod}, 0)
+	require.Equal(t, uint64(3), st.PodsMeta().Generation)
+
+	workload := workloads.Workload{Kind: workloads.KindDeployment, Namespace: "default", Name: "api", Age: "1m"}
+	st.ReplaceWorkloads([]workloads.Workload{workload}, 0)
+	workload.Age = "2m"
+	st.ReplaceWorkloads([]workloads.Workload{workload}, 0)
+	require.Equal(t, uint64(1), st.WorkloadsMeta().Generation)
+
+	workload.Ready = 1
+	st.ReplaceWorkloads([]workloads.Workload{workload}, 0)
+	require.Equal(t, uint64(2), st.WorkloadsMeta().Generation)
+}
@@ -1,51 +1,225 @@
 package store
 
 import (
+	"reflect"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/JNickson/cluster-telemetry-service/internal/events"
+	"github.com/JNickson/cluster-telemetry-service/internal/history"
 	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
 	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/summary"
+	"github.com/JNickson/cluster-telemetry-service/internal/workloads"
 )
 
 type Store struct {
 	mu    sync.RWMutex
 	nodes []nodes.Node
 	pods  []pods.Pod
+
+	nodeWatch *watchLog[nodes.Node]
+	podWatch  *watchLog[pods.Pod]
+
+	workloads []workloads.Workload
+
+	// summary is rebuilt after each node or pod refresh rather than on
+	// every request.
+	summary summary.Summary
+
+	nodesMeta     SnapshotMeta
+	podsMeta      SnapshotMeta
+	workloadsMeta SnapshotMeta
+
+	events  *events.Buffer
+	history *history.History
+
+	// backend is nil unless Attach was called; the state is then kept in
+	// memory only. compactMu keeps compactions from overlapping.
+	backend      Backend
+	compactMu    sync.Mutex
+	pendingNodes pendingSamples[history.NodeSample]
+	pendingPods  pendingSamples[history.PodSample]
 }
 
 func New() *Store {
 	return &Store{
-		nodes: make([]nodes.Node, 0),
-		pods:  make([]pods.Pod, 0),
+		nodes:     make([]nodes.Node, 0),
+		pods:      make([]pods.Pod, 0),
+		workloads: make([]workloads.Workload, 0),
+		nodeWatch: newWatchLog(nodeKey, nodesEqual),
+		podWatch:  newWatchLog(podKey, podsEqual),
+		events:    events.NewBuffer(events.DefaultRetention, events.DefaultMaxEvents),
+		history:   history.New(history.DefaultRetention, history.DefaultResolution),
 	}
 }
 
-func (s *Store) ReplaceNodes(nodes []nodes.Node) {
+// WithHistory replaces the default history, for a different retention or
+// resolution. It must be called before the first snapshot is stored.
+func (s *Store) WithHistory(h *history.History) *Store {
+	s.history = h
+	return s
+}
+
+// Events returns the buffer of recent Kubernetes events. Unlike the node
+// and pod snapshots it is updated directly from the informer.
+func (s *Store) Events() *events.Buffer {
+	return s.events
+}
+
+// History returns the per-node and per-pod samples recorded from every
+// stored snapshot.
+func (s *Store) History() *history.History {
+	return s.history
+}
+
+// ReplaceNodes stores a freshly built node snapshot; took is how long the
+// build took.
+func (s *Store) ReplaceNodes(nodes []nodes.Node, took time.Duration) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	changed := s.nodeWatch.record(s.nodes, nodes)
 	s.nodes = nodes
+	s.nodesMeta.built(took, changed)
+	s.recordNodesLocked(nodes, s.nodesMeta.BuiltAt)
 }
 
 func (s *Store) ListNodes() []nodes.Node {
+	nodes, _ := s.ListNodesWithVersion()
+	return nodes
+}
+
+// ListNodesWithVersion returns the nodes together with the resource version
+// they correspond to, for use as the starting point of NodeEventsSince.
+func (s *Store) ListNodesWithVersion() ([]nodes.Node, uint64) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	out := make([]nodes.Node, len(s.nodes))
 	copy(out, s.nodes)
-	return out
+	return out, s.nodeWatch.version
+}
+
+// NodeEventsSince returns the node changes after version and a channel that
+// is closed when the next change is recorded.
+func (s *Store) NodeEventsSince(version uint64) ([]WatchEvent[nodes.Node], <-chan struct{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.nodeWatch.since(version)
 }
 
 func (s *Store) ListPods() []pods.Pod {
+	pods, _ := s.ListPodsWithVersion()
+	return pods
+}
+
+// ListPodsWithVersion returns the pods together with the resource version
+// they correspond to, for use as the starting point of PodEventsSince.
+func (s *Store) ListPodsWithVersion() ([]pods.Pod, uint64) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	out := make([]pods.Pod, len(s.pods))
 	copy(out, s.pods)
-	return out
+	return out, s.podWatch.version
 }
 
-func (s *Store) ReplacePods(pods []pods.Pod) {
+// PodEventsSince returns the pod changes after version and a channel that
+// is closed when the next change is recorded.
+func (s *Store) PodEventsSince(version uint64) ([]WatchEvent[pods.Pod], <-chan struct{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.podWatch.since(version)
+}
+
+// ReplacePods stores a freshly built pod snapshot; took is how long the
+// build took.
+func (s *Store) ReplacePods(pods []pods.Pod, took time.Duration) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	changed := s.podWatch.record(s.pods, pods)
 	s.pods = pods
+	s.podsMeta.built(took, changed)
+	s.recordPodsLocked(pods, s.podsMeta.BuiltAt)
+}
+
+// ReplaceWorkloads stores a freshly built workload snapshot; took is how long
+// the build took.
+func (s *Store) ReplaceWorkloads(workloads []workloads.Workload, took time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := !slices.EqualFunc(s.workloads, workloads, workloadsEqual)
+	s.workloads = workloads
+	s.workloadsMeta.built(took, changed)
+}
+
+// ReplaceSummary stores the rollup built from the latest node and pod
+// snapshots.
+func (s *Store) ReplaceSummary(sum summary.Summary) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summary = sum
+}
+
+// Summary returns the latest rollup; its GeneratedAt is zero until the first
+// one is stored.
+func (s *Store) Summary() summary.Summary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.summary
+}
+
+func nodeKey(n nodes.Node) string {
+	return n.Name
+}
+
+func podKey(p pods.Pod) string {
+	return p.Namespace + "/" + p.Name
+}
+
+// nodesEqual ignores Age, which is rendered relative to the build time, and
+// the metrics.k8s.io usage and its timestamp, which change on every rebuild
+// without the node itself changing. Reporting them would mark every node
+// MODIFIED on each refresh and flood the watch history.
+func nodesEqual(a, b nodes.Node) bool {
+	a.Age, b.Age = "", ""
+	a.MetricsTimestamp, b.MetricsTimestamp = nil, nil
+	a.CPU.Used, b.CPU.Used = "", ""
+	a.Memory.Used, b.Memory.Used = "", ""
+	return reflect.DeepEqual(a, b)
+}
+
+// podsEqual ignores Age and the pod and container usage for the same reason
+// as nodesEqual.
+func podsEqual(a, b pods.Pod) bool {
+	a, b = withoutVolatile(a), withoutVolatile(b)
+	return reflect.DeepEqual(a, b)
+}
+
+// workloadsEqual ignores Age for the same reason as nodesEqual.
+func workloadsEqual(a, b workloads.Workload) bool {
+	a.Age, b.Age = "", ""
+	return reflect.DeepEqual(a, b)
+}
+
+func withoutVolatile(p pods.Pod) pods.Pod {
+	p.Age = ""
+	p.Usage = nil
+	p.Containers = withoutUsage(p.Containers)
+	p.InitContainers = withoutUsage(p.InitContainers)
+	return p
+}
+
+func withoutUsage(containers []pods.Container) []pods.Container {
+	if containers == nil {
+		return nil
+	}
+
+	out := make([]pods.Container, len(containers))
+	for i, c := range containers {
+		c.Usage = nil
+		out[i] = c
+	}
+	return out
 }
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventAdded    EventType = "ADDED"
+	EventModified EventType = "MODIFIED"
+	EventDeleted  EventType = "DELETED"
+)
+
+const defaultWatchHistory = 4096
+
+// ErrResourceVersionTooOld is returned when a watcher asks to resume from a
+// resource version that is no longer (or was never) in the change history.
+var ErrResourceVersionTooOld = errors.New("resource version too old")
+
+type WatchEvent[T any] struct {
+	Type            EventType
+	Object          T
+	ResourceVersion uint64
+}
+
+// watchLog keeps a bounded history of changes between snapshots so watchers
+// can catch up from the last resource version they saw. It is not safe for
+// concurrent use; the Store lock guards it.
+type watchLog[T any] struct {
+	key     func(T) string
+	equal   func(a, b T) bool
+	limit   int
+	version uint64
+	history []WatchEvent[T]
+	changed chan struct{}
+}
+
+func newWatchLog[T any](key func(T) string, equal func(a, b T) bool) *watchLog[T] {
+	return &watchLog[T]{
+		key:     key,
+		equal:   equal,
+		limit:   defaultWatchHistory,
+		version: watchEpoch(),
+		changed: make(chan struct{}),
+	}
+}
+
+// watchEpoch is the resource version a new watch log starts from. Versions
+// are not persisted, so each process starts at its boot time in milliseconds
+// times a million: a version handed out by an earlier process is always
+// below it and is rejected as too old rather than matched against an
+// unrelated sequence.
+func watchEpoch() uint64 {
+	return uint64(time.Now().UnixMilli()) * 1_000_000
+}
+
+// record diffs prev against next and appends an event for every added,
+// modified and deleted entry. It reports whether there were any.
+func (l *watchLog[T]) record(prev, next []T) bool {
+	prevByKey := make(map[string]T, len(prev))
+	for _, item := range prev {
+		prevByKey[l.key(item)] = item
+	}
+
+	nextByKey := make(map[string]T, len(next))
+	for _, item := range next {
+		nextByKey[l.key(item)] = item
+	}
+
+	var events []WatchEvent[T]
+
+	for _, k := range sortedKeys(nextByKey) {
+		item := nextByKey[k]
+		old, existed := prevByKey[k]
+
+		switch {
+		case !existed:
+			events = append(events, WatchEvent[T]{Type: EventAdded, Object: item})
+		case !l.equal(old, item):
+			events = append(events, WatchEvent[T]{Type: EventModified, Object: item})
+		}
+	}
+
+	for _, k := range sortedKeys(prevByKey) {
+		if _, ok := nextByKey[k]; !ok {
+			events = append(events, WatchEvent[T]{Type: EventDeleted, Object: prevByKey[k]})
+		}
+	}
+
+	if len(events) == 0 {
+		return false
+	}
+
+	for i := range events {
+		l.version++
+		events[i].ResourceVersion = l.version
+	}
+
+	l.history = append(l.history, events...)
+	if over := len(l.history) - l.limit; over > 0 {
+		l.history = append([]WatchEvent[T](nil), l.history[over:]...)
+	}
+
+	close(l.changed)
+	l.changed = make(chan struct{})
+
+	return true
+}
+
+// since returns the events after version and a channel that is closed on
+// the next change.
+func (l *watchLog[T]) since(version uint64) ([]WatchEvent[T], <-chan struct{}, error) {
+	if version > l.version {
+		return nil, nil, ErrResourceVersionTooOld
+	}
+
+	if version == l.version {
+		return nil, l.changed, nil
+	}
+
+	if len(l.history) == 0 || version+1 < l.history[0].ResourceVersion {
+		return nil, nil, ErrResourceVersionTooOld
+	}
+
+	start := int(version + 1 - l.history[0].ResourceVersion)
+	out := make([]WatchEvent[T], len(l.history)-start)
+	copy(out, l.history[start:])
+
+	return out, l.changed, nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
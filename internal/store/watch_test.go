@@ -0,0 +1,130 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorePodEventsSince(t *testing.T) {
+	st := New()
+	_, base := st.ListPodsWithVersion()
+
+	st.ReplacePods([]pods.Pod{
+		{Namespace: "default", Name: "api-0", Phase: "Pending", Age: "1m"},
+		{Namespace: "default", Name: "api-1", Phase: "Running", Age: "1m"},
+	}, 0)
+
+	_, version := st.ListPodsWithVersion()
+	require.Equal(t, base+2, version)
+
+	st.ReplacePods([]pods.Pod{
+		{Namespace: "default", Name: "api-0", Phase: "Running", Age: "2m"},
+		{Namespace: "default", Name: "api-1", Phase: "Running", Age: "2m"},
+		{Namespace: "jobs", Name: "worker-0", Phase: "Pending", Age: "0m"},
+	}, 0)
+
+	events, changed, err := st.PodEventsSince(version)
+	require.NoError(t, err)
+	require.NotNil(t, changed)
+	require.Equal(t, []EventType{EventModified, EventAdded}, eventTypes(events))
+	require.Equal(t, "api-0", events[0].Object.Name)
+	require.Equal(t, base+3, events[0].ResourceVersion)
+	require.Equal(t, "worker-0", events[1].Object.Name)
+
+	st.ReplacePods([]pods.Pod{
+		{Namespace: "default", Name: "api-0", Phase: "Running", Age: "3m"},
+	}, 0)
+
+	select {
+	case <-changed:
+	default:
+		t.Fatal("expected change notification")
+	}
+
+	events, _, err = st.PodEventsSince(base + 4)
+	require.NoError(t, err)
+	require.Equal(t, []EventType{EventDeleted, EventDeleted}, eventTypes(events))
+	require.Equal(t, "api-1", events[0].Object.Name)
+	require.Equal(t, "worker-0", events[1].Object.Name)
+
+	events, _, err = st.PodEventsSince(base + 6)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	_, _, err = st.PodEventsSince(base + 7)
+	require.ErrorIs(t, err, ErrResourceVersionTooOld)
+}
+
+func TestWatchLogRejectsVersionsFromEarlierProcess(t *testing.T) {
+	previous := newWatchLog(podKey, podsEqual)
+	previous.record(nil, []pods.Pod{{Name: "a"}, {Name: "b"}})
+	stale := previous.version
+
+	time.Sleep(2 * time.Millisecond)
+	log := newWatchLog(podKey, podsEqual)
+	log.record(nil, []pods.Pod{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+
+	_, _, err := log.since(stale)
+	require.ErrorIs(t, err, ErrResourceVersionTooOld)
+
+	_, _, err = log.since(2)
+	require.ErrorIs(t, err, ErrResourceVersionTooOld)
+}
+
+func TestPodsEqualIgnoresUsage(t *testing.T) {
+	a := pods.Pod{
+		Name:       "api-0",
+		Age:        "1m",
+		Usage:      &pods.ResourceUsage{CPU: "10m"},
+		Containers: []pods.Container{{Name: "app", Usage: &pods.ResourceUsage{CPU: "10m"}}},
+	}
+	b := pods.Pod{
+		Name:       "api-0",
+		Age:        "2m",
+		Usage:      &pods.ResourceUsage{CPU: "20m"},
+		Containers: []pods.Container{{Name: "app", Usage: &pods.ResourceUsage{CPU: "20m"}}},
+	}
+	require.True(t, podsEqual(a, b))
+	require.NotNil(t, a.Containers[0].Usage)
+
+	b.Containers[0].Ready = true
+	require.False(t, podsEqual(a, b))
+}
+
+func TestNodesEqualIgnoresUsage(t *testing.T) {
+	first, second := time.Unix(100, 0), time.Unix(200, 0)
+	a := nodes.Node{Name: "node-a", MetricsAvailable: true, MetricsTimestamp: &first, CPU: nodes.Usage{Used: "100m", Total: "4"}}
+	b := nodes.Node{Name: "node-a", MetricsAvailable: true, MetricsTimestamp: &second, CPU: nodes.Usage{Used: "300m", Total: "4"}}
+	require.True(t, nodesEqual(a, b))
+
+	b.MetricsAvailable = false
+	require.False(t, nodesEqual(a, b))
+}
+
+func TestWatchLogTrimsHistory(t *testing.T) {
+	log := newWatchLog(podKey, podsEqual)
+	log.limit = 2
+	base := log.version
+
+	log.record(nil, []pods.Pod{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+
+	_, _, err := log.since(base)
+	require.ErrorIs(t, err, ErrResourceVersionTooOld)
+
+	events, _, err := log.since(base + 1)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, "b", events[0].Object.Name)
+}
+
+func eventTypes[T any](events []WatchEvent[T]) []EventType {
+	out := make([]EventType, 0, len(events))
+	for _, ev := range events {
+		out = append(out, ev.Type)
+	}
+	return out
+}
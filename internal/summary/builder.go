@@ -0,0 +1,222 @@
+package summary
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Build aggregates the node snapshot and every pod in podList, which comes
+// from the informer cache so that pods not yet scheduled and completed pods
+// are counted too. Cluster and node pool requests are the committed requests
+// reported in each node's allocation, so they line up with allocatable;
+// namespace requests are the effective requests of the namespace's
+// non-terminated pods, computed the same way with nodes.PodResources. Usage
+// comes from the pod snapshot, which only holds scheduled pods.
+func Build(nodeList []nodes.Node, podList []*v1.Pod, snapshot []pods.Pod, nodePoolLabel string) Summary {
+	out := Summary{
+		GeneratedAt:   utils.Now().UTC(),
+		NodePoolLabel: nodePoolLabel,
+		Pods:          newPodCounts(),
+		Namespaces:    []NamespaceSummary{},
+		NodePools:     []NodePoolSummary{},
+	}
+
+	var cpu, memory resourceTotals
+	pools := map[string]*poolTotals{}
+	poolByNode := make(map[string]string, len(nodeList))
+
+	for _, n := range nodeList {
+		out.Nodes.add(n)
+		cpu.addNode(n.CPU, n.Allocation.CPU)
+		memory.addNode(n.Memory, n.Allocation.Memory)
+
+		if nodePoolLabel == "" {
+			continue
+		}
+
+		name := n.Labels[nodePoolLabel]
+		if name == "" {
+			name = UnlabeledPool
+		}
+		poolByNode[n.Name] = name
+
+		pool := pools[name]
+		if pool == nil {
+			pool = &poolTotals{pods: newPodCounts()}
+			pools[name] = pool
+		}
+		pool.nodes.add(n)
+		pool.cpu.addNode(n.CPU, n.Allocation.CPU)
+		pool.memory.addNode(n.Memory, n.Allocation.Memory)
+	}
+
+	namespaces := map[string]*namespaceTotals{}
+	namespace := func(name string) *namespaceTotals {
+		ns := namespaces[name]
+		if ns == nil {
+			ns = &namespaceTotals{pods: newPodCounts()}
+			namespaces[name] = ns
+		}
+		return ns
+	}
+
+	for _, p := range podList {
+		out.Pods.add(p)
+
+		ns := namespace(p.Namespace)
+		ns.pods.add(p)
+		ns.addRequests(p)
+
+		if name, ok := poolByNode[p.Spec.NodeName]; ok {
+			pools[name].pods.add(p)
+		}
+	}
+
+	for _, p := range snapshot {
+		if p.Usage != nil {
+			ns := namespace(p.Namespace)
+			ns.cpu.addUsed(p.Usage.CPU)
+			ns.memory.addUsed(p.Usage.Memory)
+		}
+	}
+
+	out.CPU = cpu.resource(formatMilli)
+	out.Memory = memory.resource(formatMebi)
+
+	for name, ns := range namespaces {
+		out.Namespaces = append(out.Namespaces, NamespaceSummary{
+			Name:   name,
+			Pods:   ns.pods,
+			CPU:    ns.cpu.resource(formatMilli),
+			Memory: ns.memory.resource(formatMebi),
+		})
+	}
+	sort.Slice(out.Namespaces, func(i, j int) bool {
+		return out.Namespaces[i].Name < out.Namespaces[j].Name
+	})
+
+	for name, pool := range pools {
+		out.NodePools = append(out.NodePools, NodePoolSummary{
+			Name:   name,
+			Nodes:  pool.nodes,
+			Pods:   pool.pods,
+			CPU:    pool.cpu.resource(formatMilli),
+			Memory: pool.memory.resource(formatMebi),
+		})
+	}
+	sort.Slice(out.NodePools, func(i, j int) bool {
+		return out.NodePools[i].Name < out.NodePools[j].Name
+	})
+
+	return out
+}
+
+type poolTotals struct {
+	nodes       NodeCounts
+	pods        PodCounts
+	cpu, memory resourceTotals
+}
+
+type namespaceTotals struct {
+	pods        PodCounts
+	cpu, memory resourceTotals
+}
+
+// addRequests adds the effective requests of p unless it has terminated.
+func (t *namespaceTotals) addRequests(p *v1.Pod) {
+	if p.Status.Phase == v1.PodSucceeded || p.Status.Phase == v1.PodFailed {
+		return
+	}
+
+	requests, _ := nodes.PodResources(p)
+	t.cpu.requests.Add(requests[v1.ResourceCPU])
+	t.memory.requests.Add(requests[v1.ResourceMemory])
+}
+
+func newPodCounts() PodCounts {
+	return PodCounts{ByPhase: map[string]int{}}
+}
+
+func (c *PodCounts) add(p *v1.Pod) {
+	c.Total++
+	c.Restarts += int64(pods.RestartCount(p))
+
+	phase := string(p.Status.Phase)
+	if phase == "" {
+		phase = "Unknown"
+	}
+	c.ByPhase[phase]++
+}
+
+func (c *NodeCounts) add(n nodes.Node) {
+	c.Total++
+	if n.Ready {
+		c.Ready++
+	} else {
+		c.NotReady++
+	}
+}
+
+// resourceTotals sums snapshot quantity strings such as "250m" or "512Mi";
+// empty or unparsable values are skipped.
+type resourceTotals struct {
+	used, requests, allocatable resource.Quantity
+	hasUsed                     bool
+}
+
+func (t *resourceTotals) addNode(usage nodes.Usage, alloc nodes.ResourceAllocation) {
+	t.addUsed(usage.Used)
+	t.addRequests(alloc.Requests)
+	addQuantity(&t.allocatable, usage.Total)
+}
+
+func (t *resourceTotals) addUsed(raw string) {
+	if addQuantity(&t.used, raw) {
+		t.hasUsed = true
+	}
+}
+
+func (t *resourceTotals) addRequests(raw string) {
+	addQuantity(&t.requests, raw)
+}
+
+func (t resourceTotals) resource(format func(resource.Quantity) string) Resource {
+	out := Resource{
+		Requests: format(t.requests),
+	}
+	if t.hasUsed {
+		out.Used = format(t.used)
+	}
+	if !t.allocatable.IsZero() {
+		out.Allocatable = format(t.allocatable)
+	}
+	return out
+}
+
+func addQuantity(total *resource.Quantity, raw string) bool {
+	if raw == "" {
+		return false
+	}
+
+	q, err := resource.ParseQuantity(raw)
+	if err != nil {
+		return false
+	}
+
+	total.Add(q)
+	return true
+}
+
+func formatMilli(q resource.Quantity) string {
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+func formatMebi(q resource.Quantity) string {
+	return fmt.Sprintf("%dMi", q.Value()/(1024*1024))
+}
@@ -0,0 +1,27 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/nodes"
+	"github.com/JNickson/cluster-telemetry-service/internal/pods"
+	"github.com/JNickson/cluster-telemetry-service/internal/testutil"
+	v1 "k8s.io/api/core/v1"
+)
+
+type buildInput struct {
+	Nodes         []nodes.Node `json:"nodes"`
+	Pods          []*v1.Pod    `json:"pods"`
+	Snapshot      []pods.Pod   `json:"snapshot"`
+	NodePoolLabel string       `json:"nodePoolLabel"`
+}
+
+func TestBuild(t *testing.T) {
+	testutil.RunGoldenTest(
+		t,
+		"testdata/build",
+		func(input buildInput) Summary {
+			return Build(input.Nodes, input.Pods, input.Snapshot, input.NodePoolLabel)
+		},
+	)
+}
@@ -0,0 +1,62 @@
+package summary
+
+import "time"
+
+// UnlabeledPool is the node pool of nodes that do not carry the node pool
+// label.
+const UnlabeledPool = "<none>"
+
+// Summary rolls the node and pod snapshots up into cluster totals with
+// breakdowns per namespace and per node pool.
+type Summary struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	// NodePoolLabel is the node label NodePools are grouped by; empty when
+	// the breakdown is disabled.
+	NodePoolLabel string `json:"nodePoolLabel"`
+
+	Nodes NodeCounts `json:"nodes"`
+	Pods  PodCounts  `json:"pods"`
+
+	CPU    Resource `json:"cpu"`
+	Memory Resource `json:"memory"`
+
+	Namespaces []NamespaceSummary `json:"namespaces"`
+	NodePools  []NodePoolSummary  `json:"nodePools"`
+}
+
+type NodeCounts struct {
+	Total    int `json:"total"`
+	Ready    int `json:"ready"`
+	NotReady int `json:"notReady"`
+}
+
+type PodCounts struct {
+	Total    int            `json:"total"`
+	ByPhase  map[string]int `json:"byPhase"`
+	Restarts int64          `json:"restarts"`
+}
+
+// Resource holds the summed usage, requests and allocatable capacity of one
+// resource. Used is empty when metrics.k8s.io had no sample for any of the
+// summed objects; namespaces have no Allocatable.
+type Resource struct {
+	Used        string `json:"used"`
+	Requests    string `json:"requests"`
+	Allocatable string `json:"allocatable,omitempty"`
+}
+
+type NamespaceSummary struct {
+	Name   string    `json:"name"`
+	Pods   PodCounts `json:"pods"`
+	CPU    Resource  `json:"cpu"`
+	Memory Resource  `json:"memory"`
+}
+
+type NodePoolSummary struct {
+	Name   string     `json:"name"`
+	Nodes  NodeCounts `json:"nodes"`
+	Pods   PodCounts  `json:"pods"`
+	CPU    Resource   `json:"cpu"`
+	Memory Resource   `json:"memory"`
+}
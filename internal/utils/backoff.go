@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultBackoffInitial and DefaultBackoffMax space out retries of optional
+// APIs such as metrics.k8s.io.
+const (
+	DefaultBackoffInitial = 10 * time.Second
+	DefaultBackoffMax     = 5 * time.Minute
+)
+
+// Backoff spaces out retries of a failing call. Each consecutive failure
+// doubles the wait, starting at Initial and capped at Max; a success resets
+// it. It is safe for concurrent use.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+
+	mu       sync.Mutex
+	failures int
+	next     time.Time
+}
+
+// NewBackoff returns a Backoff with DefaultBackoffInitial and
+// DefaultBackoffMax.
+func NewBackoff() *Backoff {
+	return &Backoff{
+		Initial: DefaultBackoffInitial,
+		Max:     DefaultBackoffMax,
+	}
+}
+
+// Ready reports whether the next attempt may be made now.
+func (b *Backoff) Ready() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return !Now().Before(b.next)
+}
+
+// Failure records a failed attempt and returns the time to wait before the
+// next one.
+func (b *Backoff) Failure() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wait := b.Initial << min(b.failures, 30)
+	if wait <= 0 || wait > b.Max {
+		wait = b.Max
+	}
+
+	b.failures++
+	b.next = Now().Add(wait)
+
+	return wait
+}
+
+// Success resets the backoff after a successful attempt.
+func (b *Backoff) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.next = time.Time{}
+}
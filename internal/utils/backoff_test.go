@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := Now
+	Now = func() time.Time { return now }
+	defer func() { Now = originalNow }()
+
+	b := &Backoff{Initial: time.Second, Max: 5 * time.Second}
+	require.True(t, b.Ready())
+
+	require.Equal(t, time.Second, b.Failure())
+	require.False(t, b.Ready())
+
+	now = now.Add(time.Second)
+	require.True(t, b.Ready())
+
+	require.Equal(t, 2*time.Second, b.Failure())
+	require.Equal(t, 4*time.Second, b.Failure())
+	require.Equal(t, 5*time.Second, b.Failure(), "capped at Max")
+	require.Equal(t, 5*time.Second, b.Failure())
+
+	now = now.Add(4 * time.Second)
+	require.False(t, b.Ready())
+
+	b.Success()
+	require.True(t, b.Ready())
+	require.Equal(t, time.Second, b.Failure(), "success resets the wait")
+}
+
+func TestBackoffDoesNotOverflow(t *testing.T) {
+	b := NewBackoff()
+	for range 100 {
+		require.LessOrEqual(t, b.Failure(), DefaultBackoffMax)
+	}
+	require.Equal(t, DefaultBackoffMax, b.Failure())
+}
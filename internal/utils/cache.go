@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL matches the default metrics-server resolution, so a cached
+// metrics.k8s.io list is reused until fresher samples can exist.
+const DefaultCacheTTL = 15 * time.Second
+
+// Cache holds the last value of a call that is expensive to repeat and
+// reuses it until it is older than TTL. It is safe for concurrent use.
+type Cache[T any] struct {
+	TTL time.Duration
+
+	mu        sync.Mutex
+	value     T
+	fetchedAt time.Time
+	ok        bool
+}
+
+// NewCache returns a Cache with DefaultCacheTTL.
+func NewCache[T any]() *Cache[T] {
+	return &Cache[T]{TTL: DefaultCacheTTL}
+}
+
+// Get returns the cached value and whether it is still fresh.
+func (c *Cache[T]) Get() (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.ok || Now().Sub(c.fetchedAt) >= c.TTL {
+		var zero T
+		return zero, false
+	}
+
+	return c.value, true
+}
+
+// Set stores v as the freshest value.
+func (c *Cache[T]) Set(v T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.value = v
+	c.fetchedAt = Now()
+	c.ok = true
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := Now
+	Now = func() time.Time { return now }
+	defer func() { Now = originalNow }()
+
+	c := &Cache[int]{TTL: 10 * time.Second}
+	_, ok := c.Get()
+	require.False(t, ok, "empty cache")
+
+	c.Set(1)
+	v, ok := c.Get()
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+
+	now = now.Add(9 * time.Second)
+	v, ok = c.Get()
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+
+	now = now.Add(time.Second)
+	_, ok = c.Get()
+	require.False(t, ok, "expired at TTL")
+
+	c.Set(2)
+	v, ok = c.Get()
+	require.True(t, ok)
+	require.Equal(t, 2, v)
+}
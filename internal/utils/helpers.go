@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 )
 
@@ -33,10 +32,6 @@ func AgeSinceWithNow(t, now time.Time) string {
 
 func Int64Ptr(i int64) *int64 { return &i }
 
-func LastIndex(s, sep string) int {
-	return strings.LastIndex(s, sep)
-}
-
 func MapValuesToSlice[K comparable, V any](m map[K]V) []V {
 	out := make([]V, 0, len(m))
 	for _, v := range m {
@@ -0,0 +1,87 @@
+package workloads
+
+import "time"
+
+const (
+	KindDeployment  = "Deployment"
+	KindStatefulSet = "StatefulSet"
+	KindDaemonSet   = "DaemonSet"
+	KindReplicaSet  = "ReplicaSet"
+	KindJob         = "Job"
+	KindCronJob     = "CronJob"
+	KindPod         = "Pod"
+
+	// KindRollout is an Argo Rollouts rollout. It owns ReplicaSets the way a
+	// Deployment does and is grouped with Deployments.
+	KindRollout = "Rollout"
+)
+
+const (
+	RolloutComplete    = "Complete"
+	RolloutProgressing = "Progressing"
+	RolloutPaused      = "Paused"
+	RolloutFailed      = "Failed"
+	RolloutSuspended   = "Suspended"
+	RolloutRunning     = "Running"
+	RolloutScheduled   = "Scheduled"
+)
+
+type Condition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+type PodRef struct {
+	Name  string `json:"name"`
+	Node  string `json:"node"`
+	Phase string `json:"phase"`
+	Ready bool   `json:"ready"`
+}
+
+type Workload struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Age       string `json:"age"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	Labels map[string]string `json:"labels"`
+
+	Desired   int32 `json:"desired"`
+	Ready     int32 `json:"ready"`
+	Available int32 `json:"available"`
+	Updated   int32 `json:"updated"`
+
+	RolloutStatus string      `json:"rolloutStatus"`
+	Conditions    []Condition `json:"conditions"`
+
+	Job     *JobStatus     `json:"job,omitempty"`
+	CronJob *CronJobStatus `json:"cronJob,omitempty"`
+
+	Pods []PodRef `json:"pods"`
+}
+
+type JobStatus struct {
+	Active    int32 `json:"active"`
+	Succeeded int32 `json:"succeeded"`
+	Failed    int32 `json:"failed"`
+}
+
+type CronJobStatus struct {
+	Schedule           string     `json:"schedule"`
+	Suspended          bool       `json:"suspended"`
+	ActiveJobs         int        `json:"activeJobs"`
+	LastScheduleTime   *time.Time `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime *time.Time `json:"lastSuccessfulTime,omitempty"`
+}
+
+// Owner identifies the top-level controller of a pod, or the pod itself
+// when nothing controls it.
+type Owner struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
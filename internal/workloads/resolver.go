@@ -0,0 +1,61 @@
+package workloads
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+)
+
+// OwnerResolver walks a pod's controller references up to the workload
+// that manages it: Pod -> ReplicaSet -> Deployment, Pod -> Job -> CronJob,
+// or directly to a StatefulSet or DaemonSet. Intermediate objects are read
+// from the informer listers, so names are never guessed from suffixes.
+type OwnerResolver struct {
+	replicaSets appsv1listers.ReplicaSetLister
+	jobs        batchv1listers.JobLister
+}
+
+func NewOwnerResolver(
+	replicaSets appsv1listers.ReplicaSetLister,
+	jobs batchv1listers.JobLister,
+) *OwnerResolver {
+	return &OwnerResolver{
+		replicaSets: replicaSets,
+		jobs:        jobs,
+	}
+}
+
+// Resolve returns the top-level owner of pod. A pod without a controller
+// resolves to itself. When an intermediate ReplicaSet or Job is not in the
+// cache (or has no controller of its own), it is returned as the owner.
+func (r *OwnerResolver) Resolve(pod *v1.Pod) Owner {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return Owner{Kind: KindPod, Namespace: pod.Namespace, Name: pod.Name}
+	}
+
+	owner := Owner{Kind: ref.Kind, Namespace: pod.Namespace, Name: ref.Name}
+
+	switch ref.Kind {
+	case KindReplicaSet:
+		rs, err := r.replicaSets.ReplicaSets(pod.Namespace).Get(ref.Name)
+		if err != nil {
+			return owner
+		}
+		if parent := metav1.GetControllerOf(rs); parent != nil {
+			return Owner{Kind: parent.Kind, Namespace: pod.Namespace, Name: parent.Name}
+		}
+
+	case KindJob:
+		job, err := r.jobs.Jobs(pod.Namespace).Get(ref.Name)
+		if err != nil {
+			return owner
+		}
+		if parent := metav1.GetControllerOf(job); parent != nil {
+			return Owner{Kind: parent.Kind, Namespace: pod.Namespace, Name: parent.Name}
+		}
+	}
+
+	return owner
+}
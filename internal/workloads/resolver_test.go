@@ -0,0 +1,85 @@
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOwnerResolverResolve(t *testing.T) {
+	svc := newTestService(t, clusterState{
+		ReplicaSets: []appsv1.ReplicaSet{
+			{ObjectMeta: metav1.ObjectMeta{Name: "checkout-v2", Namespace: "shop", OwnerReferences: []metav1.OwnerReference{controllerRef("Deployment", "checkout")}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cart-6d4f9", Namespace: "shop", OwnerReferences: []metav1.OwnerReference{controllerRef(KindRollout, "cart")}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "orphan-5c7b8", Namespace: "shop"}},
+		},
+		Jobs: []batchv1.Job{
+			{ObjectMeta: metav1.ObjectMeta{Name: "nightly-29430720", Namespace: "ops", OwnerReferences: []metav1.OwnerReference{controllerRef("CronJob", "nightly")}}},
+		},
+	})
+
+	tests := []struct {
+		name  string
+		pod   *v1.Pod
+		owner Owner
+	}{
+		{
+			name:  "deployment through replicaset without hash suffix",
+			pod:   ownedPod("shop", "checkout-v2-abcde", "ReplicaSet", "checkout-v2"),
+			owner: Owner{Kind: KindDeployment, Namespace: "shop", Name: "checkout"},
+		},
+		{
+			name:  "argo rollout through replicaset",
+			pod:   ownedPod("shop", "cart-6d4f9-xyz12", "ReplicaSet", "cart-6d4f9"),
+			owner: Owner{Kind: KindRollout, Namespace: "shop", Name: "cart"},
+		},
+		{
+			name:  "bare replicaset",
+			pod:   ownedPod("shop", "orphan-5c7b8-q1w2e", "ReplicaSet", "orphan-5c7b8"),
+			owner: Owner{Kind: KindReplicaSet, Namespace: "shop", Name: "orphan-5c7b8"},
+		},
+		{
+			name:  "replicaset missing from cache",
+			pod:   ownedPod("shop", "gone-7f8g9-a1s2d", "ReplicaSet", "gone-7f8g9"),
+			owner: Owner{Kind: KindReplicaSet, Namespace: "shop", Name: "gone-7f8g9"},
+		},
+		{
+			name:  "cronjob through job",
+			pod:   ownedPod("ops", "nightly-29430720-z9x8c", "Job", "nightly-29430720"),
+			owner: Owner{Kind: KindCronJob, Namespace: "ops", Name: "nightly"},
+		},
+		{
+			name:  "statefulset",
+			pod:   ownedPod("data", "db-0", "StatefulSet", "db"),
+			owner: Owner{Kind: KindStatefulSet, Namespace: "data", Name: "db"},
+		},
+		{
+			name:  "standalone pod",
+			pod:   &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "default"}},
+			owner: Owner{Kind: KindPod, Namespace: "default", Name: "debug"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.owner, svc.resolver.Resolve(tt.pod))
+		})
+	}
+}
+
+func controllerRef(kind, name string) metav1.OwnerReference {
+	controller := true
+	return metav1.OwnerReference{Kind: kind, Name: name, Controller: &controller}
+}
+
+func ownedPod(namespace, name, kind, owner string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       namespace,
+		OwnerReferences: []metav1.OwnerReference{controllerRef(kind, owner)},
+	}}
+}
@@ -0,0 +1,379 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+)
+
+type Service interface {
+	BuildSnapshot(ctx context.Context) ([]Workload, error)
+}
+
+type WorkloadService struct {
+	deployments  appsv1listers.DeploymentLister
+	statefulSets appsv1listers.StatefulSetLister
+	daemonSets   appsv1listers.DaemonSetLister
+	replicaSets  appsv1listers.ReplicaSetLister
+	jobs         batchv1listers.JobLister
+	cronJobs     batchv1listers.CronJobLister
+	podLister    corev1listers.PodLister
+	resolver     *OwnerResolver
+}
+
+func NewWorkloadService(
+	deployments appsv1listers.DeploymentLister,
+	statefulSets appsv1listers.StatefulSetLister,
+	daemonSets appsv1listers.DaemonSetLister,
+	replicaSets appsv1listers.ReplicaSetLister,
+	jobs batchv1listers.JobLister,
+	cronJobs batchv1listers.CronJobLister,
+	podLister corev1listers.PodLister,
+) *WorkloadService {
+	return &WorkloadService{
+		deployments:  deployments,
+		statefulSets: statefulSets,
+		daemonSets:   daemonSets,
+		replicaSets:  replicaSets,
+		jobs:         jobs,
+		cronJobs:     cronJobs,
+		podLister:    podLister,
+		resolver:     NewOwnerResolver(replicaSets, jobs),
+	}
+}
+
+func (s *WorkloadService) BuildSnapshot(ctx context.Context) ([]Workload, error) {
+
+	allPods, err := s.podLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	podsByOwner := make(map[Owner][]PodRef)
+	for _, p := range allPods {
+		owner := s.resolver.Resolve(p)
+		podsByOwner[owner] = append(podsByOwner[owner], PodRef{
+			Name:  p.Name,
+			Node:  p.Spec.NodeName,
+			Phase: string(p.Status.Phase),
+			Ready: podReady(p),
+		})
+	}
+
+	var out []Workload
+
+	deployments, err := s.deployments.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments {
+		out = append(out, mapDeployment(d, podsByOwner[ownerOf(KindDeployment, d.ObjectMeta)]))
+	}
+
+	statefulSets, err := s.statefulSets.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, sts := range statefulSets {
+		out = append(out, mapStatefulSet(sts, podsByOwner[ownerOf(KindStatefulSet, sts.ObjectMeta)]))
+	}
+
+	daemonSets, err := s.daemonSets.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for _, ds := range daemonSets {
+		out = append(out, mapDaemonSet(ds, podsByOwner[ownerOf(KindDaemonSet, ds.ObjectMeta)]))
+	}
+
+	// ReplicaSets and Jobs managed by a Deployment or CronJob are folded
+	// into their owner; only standalone ones are workloads of their own.
+	replicaSets, err := s.replicaSets.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets: %w", err)
+	}
+	for _, rs := range replicaSets {
+		if metav1.GetControllerOf(rs) == nil {
+			out = append(out, mapReplicaSet(rs, podsByOwner[ownerOf(KindReplicaSet, rs.ObjectMeta)]))
+		}
+	}
+
+	jobs, err := s.jobs.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for _, j := range jobs {
+		if metav1.GetControllerOf(j) == nil {
+			out = append(out, mapJob(j, podsByOwner[ownerOf(KindJob, j.ObjectMeta)]))
+		}
+	}
+
+	cronJobs, err := s.cronJobs.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+	for _, cj := range cronJobs {
+		out = append(out, mapCronJob(cj, podsByOwner[ownerOf(KindCronJob, cj.ObjectMeta)]))
+	}
+
+	sortWorkloads(out)
+
+	return out, nil
+}
+
+func ownerOf(kind string, meta metav1.ObjectMeta) Owner {
+	return Owner{Kind: kind, Namespace: meta.Namespace, Name: meta.Name}
+}
+
+func newWorkload(kind string, meta metav1.ObjectMeta, pods []PodRef) Workload {
+	if pods == nil {
+		pods = []PodRef{}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	return Workload{
+		Kind:       kind,
+		Namespace:  meta.Namespace,
+		Name:       meta.Name,
+		Age:        utils.AgeSince(meta.CreationTimestamp.Time),
+		CreatedAt:  meta.CreationTimestamp.UTC(),
+		Labels:     meta.Labels,
+		Conditions: []Condition{},
+		Pods:       pods,
+	}
+}
+
+func mapDeployment(d *appsv1.Deployment, pods []PodRef) Workload {
+	w := newWorkload(KindDeployment, d.ObjectMeta, pods)
+
+	w.Desired = replicasOrDefault(d.Spec.Replicas)
+	w.Ready = d.Status.ReadyReplicas
+	w.Available = d.Status.AvailableReplicas
+	w.Updated = d.Status.UpdatedReplicas
+
+	failed := false
+	for _, c := range d.Status.Conditions {
+		w.Conditions = append(w.Conditions, Condition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+		if c.Type == appsv1.DeploymentProgressing && c.Reason == "ProgressDeadlineExceeded" {
+			failed = true
+		}
+	}
+
+	// Mirrors kubectl rollout status.
+	switch {
+	case d.Spec.Paused:
+		w.RolloutStatus = RolloutPaused
+	case failed:
+		w.RolloutStatus = RolloutFailed
+	case d.Status.ObservedGeneration < d.Generation,
+		d.Status.UpdatedReplicas < w.Desired,
+		d.Status.Replicas > d.Status.UpdatedReplicas,
+		d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		w.RolloutStatus = RolloutProgressing
+	default:
+		w.RolloutStatus = RolloutComplete
+	}
+
+	return w
+}
+
+func mapStatefulSet(sts *appsv1.StatefulSet, pods []PodRef) Workload {
+	w := newWorkload(KindStatefulSet, sts.ObjectMeta, pods)
+
+	w.Desired = replicasOrDefault(sts.Spec.Replicas)
+	w.Ready = sts.Status.ReadyReplicas
+	w.Available = sts.Status.AvailableReplicas
+	w.Updated = sts.Status.UpdatedReplicas
+
+	for _, c := range sts.Status.Conditions {
+		w.Conditions = append(w.Conditions, Condition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+
+	switch {
+	case sts.Status.ObservedGeneration < sts.Generation,
+		sts.Status.UpdatedReplicas < w.Desired,
+		sts.Status.ReadyReplicas < w.Desired,
+		sts.Status.UpdateRevision != sts.Status.CurrentRevision:
+		w.RolloutStatus = RolloutProgressing
+	default:
+		w.RolloutStatus = RolloutComplete
+	}
+
+	return w
+}
+
+func mapDaemonSet(ds *appsv1.DaemonSet, pods []PodRef) Workload {
+	w := newWorkload(KindDaemonSet, ds.ObjectMeta, pods)
+
+	w.Desired = ds.Status.DesiredNumberScheduled
+	w.Ready = ds.Status.NumberReady
+	w.Available = ds.Status.NumberAvailable
+	w.Updated = ds.Status.UpdatedNumberScheduled
+
+	for _, c := range ds.Status.Conditions {
+		w.Conditions = append(w.Conditions, Condition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+
+	switch {
+	case ds.Status.ObservedGeneration < ds.Generation,
+		ds.Status.UpdatedNumberScheduled < w.Desired,
+		ds.Status.NumberAvailable < w.Desired:
+		w.RolloutStatus = RolloutProgressing
+	default:
+		w.RolloutStatus = RolloutComplete
+	}
+
+	return w
+}
+
+func mapReplicaSet(rs *appsv1.ReplicaSet, pods []PodRef) Workload {
+	w := newWorkload(KindReplicaSet, rs.ObjectMeta, pods)
+
+	w.Desired = replicasOrDefault(rs.Spec.Replicas)
+	w.Ready = rs.Status.ReadyReplicas
+	w.Available = rs.Status.AvailableReplicas
+	w.Updated = rs.Status.Replicas
+
+	for _, c := range rs.Status.Conditions {
+		w.Conditions = append(w.Conditions, Condition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+
+	if rs.Status.AvailableReplicas < w.Desired {
+		w.RolloutStatus = RolloutProgressing
+	} else {
+		w.RolloutStatus = RolloutComplete
+	}
+
+	return w
+}
+
+func mapJob(j *batchv1.Job, pods []PodRef) Workload {
+	w := newWorkload(KindJob, j.ObjectMeta, pods)
+
+	w.Desired = replicasOrDefault(j.Spec.Completions)
+	if j.Status.Ready != nil {
+		w.Ready = *j.Status.Ready
+	}
+	w.Available = j.Status.Succeeded
+
+	w.Job = &JobStatus{
+		Active:    j.Status.Active,
+		Succeeded: j.Status.Succeeded,
+		Failed:    j.Status.Failed,
+	}
+
+	w.RolloutStatus = RolloutRunning
+	if j.Spec.Suspend != nil && *j.Spec.Suspend {
+		w.RolloutStatus = RolloutSuspended
+	}
+
+	for _, c := range j.Status.Conditions {
+		w.Conditions = append(w.Conditions, Condition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+		if c.Status != v1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			w.RolloutStatus = RolloutComplete
+		case batchv1.JobFailed:
+			w.RolloutStatus = RolloutFailed
+		}
+	}
+
+	return w
+}
+
+func mapCronJob(cj *batchv1.CronJob, pods []PodRef) Workload {
+	w := newWorkload(KindCronJob, cj.ObjectMeta, pods)
+
+	suspended := cj.Spec.Suspend != nil && *cj.Spec.Suspend
+
+	w.CronJob = &CronJobStatus{
+		Schedule:   cj.Spec.Schedule,
+		Suspended:  suspended,
+		ActiveJobs: len(cj.Status.Active),
+	}
+	if t := cj.Status.LastScheduleTime; t != nil {
+		ts := t.UTC()
+		w.CronJob.LastScheduleTime = &ts
+	}
+	if t := cj.Status.LastSuccessfulTime; t != nil {
+		ts := t.UTC()
+		w.CronJob.LastSuccessfulTime = &ts
+	}
+
+	switch {
+	case suspended:
+		w.RolloutStatus = RolloutSuspended
+	case len(cj.Status.Active) > 0:
+		w.RolloutStatus = RolloutRunning
+	default:
+		w.RolloutStatus = RolloutScheduled
+	}
+
+	return w
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func podReady(p *v1.Pod) bool {
+	for _, c := range p.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func sortWorkloads(items []Workload) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+}
@@ -0,0 +1,85 @@
+package workloads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JNickson/cluster-telemetry-service/internal/testutil"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type clusterState struct {
+	Deployments  []appsv1.Deployment  `json:"deployments"`
+	StatefulSets []appsv1.StatefulSet `json:"statefulSets"`
+	DaemonSets   []appsv1.DaemonSet   `json:"daemonSets"`
+	ReplicaSets  []appsv1.ReplicaSet  `json:"replicaSets"`
+	Jobs         []batchv1.Job        `json:"jobs"`
+	CronJobs     []batchv1.CronJob    `json:"cronJobs"`
+	Pods         []v1.Pod             `json:"pods"`
+}
+
+func newTestService(t *testing.T, state clusterState) *WorkloadService {
+	t.Helper()
+
+	indexer := func(objs ...any) cache.Indexer {
+		idx := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		for _, obj := range objs {
+			require.NoError(t, idx.Add(obj))
+		}
+		return idx
+	}
+
+	var deployments, statefulSets, daemonSets, replicaSets, jobs, cronJobs, pods []any
+	for i := range state.Deployments {
+		deployments = append(deployments, &state.Deployments[i])
+	}
+	for i := range state.StatefulSets {
+		statefulSets = append(statefulSets, &state.StatefulSets[i])
+	}
+	for i := range state.DaemonSets {
+		daemonSets = append(daemonSets, &state.DaemonSets[i])
+	}
+	for i := range state.ReplicaSets {
+		replicaSets = append(replicaSets, &state.ReplicaSets[i])
+	}
+	for i := range state.Jobs {
+		jobs = append(jobs, &state.Jobs[i])
+	}
+	for i := range state.CronJobs {
+		cronJobs = append(cronJobs, &state.CronJobs[i])
+	}
+	for i := range state.Pods {
+		pods = append(pods, &state.Pods[i])
+	}
+
+	return NewWorkloadService(
+		appsv1listers.NewDeploymentLister(indexer(deployments...)),
+		appsv1listers.NewStatefulSetLister(indexer(statefulSets...)),
+		appsv1listers.NewDaemonSetLister(indexer(daemonSets...)),
+		appsv1listers.NewReplicaSetLister(indexer(replicaSets...)),
+		batchv1listers.NewJobLister(indexer(jobs...)),
+		batchv1listers.NewCronJobLister(indexer(cronJobs...)),
+		corev1listers.NewPodLister(indexer(pods...)),
+	)
+}
+
+// Golden File Tests
+
+func TestBuildSnapshot(t *testing.T) {
+	testutil.RunGoldenTest(
+		t,
+		"testdata/buildSnapshot",
+		func(input clusterState) []Workload {
+			out, err := newTestService(t, input).BuildSnapshot(context.Background())
+			require.NoError(t, err)
+			return out
+		},
+	)
+}
@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/JNickson/cluster-telemetry-service/internal/clients"
+	"github.com/JNickson/cluster-telemetry-service/internal/config"
 	"github.com/JNickson/cluster-telemetry-service/internal/runtime"
 	"github.com/JNickson/cluster-telemetry-service/internal/utils"
 	"k8s.io/client-go/rest"
@@ -18,8 +21,26 @@ func setupLogger() {
 	slog.SetDefault(logger)
 }
 
-func mustKubeConfig() *rest.Config {
-	cfg, err := clients.NewKubeConfig()
+func mustConfig() (config.Config, bool) {
+	cfg, printConfig, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		slog.Error("failed to load config", "error", err)
+		os.Exit(2)
+	}
+	return cfg, printConfig
+}
+
+func mustKubeConfig(kube config.KubeConfig) *rest.Config {
+	cfg, err := clients.NewKubeConfig(clients.KubeOptions{
+		Kubeconfig: kube.Kubeconfig,
+		Context:    kube.Context,
+		QPS:        kube.QPS,
+		Burst:      kube.Burst,
+		UserAgent:  kube.UserAgent,
+	})
 	if err != nil {
 		slog.Error("failed to create kube config", "error", err)
 		os.Exit(1)
@@ -30,12 +51,21 @@ func mustKubeConfig() *rest.Config {
 func main() {
 	setupLogger()
 
+	cfg, printConfig := mustConfig()
+	if printConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			slog.Error("failed to print config", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	cfg := mustKubeConfig()
+	restCfg := mustKubeConfig(cfg.Kube)
 
-	app, err := runtime.New(cfg)
+	app, err := runtime.New(restCfg, cfg)
 	if err != nil {
 		slog.Error("failed to create app", "error", err)
 		os.Exit(1)